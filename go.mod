@@ -2,4 +2,4 @@ module mc-bot
 
 go 1.20
 
-require github.com/huntclauss/dotenv v0.0.2 // indirect
+require github.com/huntclauss/dotenv v0.0.2
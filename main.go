@@ -26,10 +26,24 @@ func main() {
 
 	go client.HandleResponses()
 
+	commands := mc.NewCommandRouter("!")
+	commands.Handle(mc.BotCommand{
+		Name: "status",
+		Help: "shows the health and position of the bot",
+		Handler: func(cmd *mc.CommandContext) error {
+			health, food, _ := cmd.Client.Player.Health()
+			x, y, z := cmd.Client.Player.Position()
+			return cmd.Reply("health %.1f, food %d at %.0f %.0f %.0f", health, food, x, y, z)
+		},
+	})
+	commands.Attach(&client)
+
 	err = client.Login("Test", "00000000-0000-4000-0000-000000000000")
 	if err != nil {
 		log.Fatalf("cannot login: %s", err)
 	}
 
+	// RunPhysics needs Client.Blocks filled from data generated for the
+	// server version, which is not part of this repository
 	select {}
 }
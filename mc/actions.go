@@ -3,6 +3,7 @@ package mc
 import (
 	"log"
 	"mc-bot/mc/proto"
+	"sync/atomic"
 )
 
 type RequestRespawn struct {
@@ -13,6 +14,184 @@ type RequestAlivePacket struct {
 	ID proto.Long
 }
 
+type RequestConfirmTeleportation struct {
+	TeleportID proto.VarInt
+}
+
+type RequestSetPlayerPosition struct {
+	X, FeetY, Z proto.Double
+	OnGround    proto.Bool
+}
+
+type RequestSetPlayerRotation struct {
+	Yaw      proto.Float
+	Pitch    proto.Float
+	OnGround proto.Bool
+}
+
+type RequestSetPlayerOnGround struct {
+	OnGround proto.Bool
+}
+
+// Player Command actions, see https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Command
+const (
+	PlayerCommandStartSneaking  = proto.VarInt(0)
+	PlayerCommandStopSneaking   = proto.VarInt(1)
+	PlayerCommandLeaveBed       = proto.VarInt(2)
+	PlayerCommandStartSprinting = proto.VarInt(3)
+	PlayerCommandStopSprinting  = proto.VarInt(4)
+)
+
+type RequestPlayerCommand struct {
+	EntityID  proto.VarInt
+	ActionID  proto.VarInt
+	JumpBoost proto.VarInt
+}
+
+const (
+	HandMain = proto.VarInt(0)
+	HandOff  = proto.VarInt(1)
+)
+
+type RequestUseItemOn struct {
+	Hand        proto.VarInt
+	Location    proto.Position
+	Face        proto.VarInt // Face is a proto.Direction
+	CursorX     proto.Float
+	CursorY     proto.Float
+	CursorZ     proto.Float
+	InsideBlock proto.Bool
+	Sequence    proto.VarInt
+}
+
+// Player Action statuses, see https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Action
+const (
+	ActionStartedDigging   = proto.VarInt(0)
+	ActionCancelledDigging = proto.VarInt(1)
+	ActionFinishedDigging  = proto.VarInt(2)
+	ActionDropItemStack    = proto.VarInt(3)
+	ActionDropItem         = proto.VarInt(4)
+	ActionReleaseUseItem   = proto.VarInt(5)
+	ActionSwapItemInHand   = proto.VarInt(6)
+)
+
+type RequestPlayerAction struct {
+	Status   proto.VarInt
+	Location proto.Position
+	Face     proto.Byte
+	Sequence proto.VarInt
+}
+
+// Interact types, see https://wiki.vg/index.php?title=Protocol&oldid=18375#Interact
+const (
+	InteractTypeInteract = proto.VarInt(0)
+	InteractTypeAttack   = proto.VarInt(1)
+	InteractTypeAt       = proto.VarInt(2)
+)
+
+type RequestSwingArm struct {
+	Hand proto.VarInt
+}
+
+type ChangedSlot struct {
+	Slot proto.Short
+	Data proto.Slot
+}
+
+type RequestClickContainer struct {
+	WindowID proto.UByte
+	StateID  proto.VarInt
+	Slot     proto.Short
+	Button   proto.Byte
+	Mode     proto.VarInt
+	Changed  proto.Array[ChangedSlot]
+	Carried  proto.Slot
+}
+
+type RequestCloseContainer struct {
+	WindowID proto.UByte
+}
+
+type RequestPlaceRecipe struct {
+	WindowID proto.Byte
+	Recipe   proto.String
+	MakeAll  proto.Bool
+}
+
+type RequestChatMessage struct {
+	Message      proto.String
+	Timestamp    proto.Long
+	Salt         proto.Long
+	Signature    proto.Optional[proto.Signature]
+	MessageCount proto.VarInt
+	Acknowledged proto.LastSeenBits
+}
+
+type ArgumentSignature struct {
+	Name      proto.String
+	Signature proto.Signature
+}
+
+type RequestChatCommand struct {
+	Command            proto.String
+	Timestamp          proto.Long
+	Salt               proto.Long
+	ArgumentSignatures proto.Array[ArgumentSignature]
+	MessageCount       proto.VarInt
+	Acknowledged       proto.LastSeenBits
+}
+
+type RequestMessageAcknowledgment struct {
+	MessageCount proto.VarInt
+}
+
+type RequestPlayerSession struct {
+	SessionID          proto.Uuid
+	PublicKeyExpiresAt proto.Long
+	PublicKey          proto.ByteArray
+	PublicKeySignature proto.ByteArray
+}
+
+type RequestCommandSuggestions struct {
+	ID   proto.VarInt
+	Text proto.String
+}
+
+type RequestUseItem struct {
+	Hand     proto.VarInt
+	Sequence proto.VarInt
+}
+
+type RequestUpdateSign struct {
+	Location    proto.Position
+	IsFrontText proto.Bool
+	Line1       proto.String
+	Line2       proto.String
+	Line3       proto.String
+	Line4       proto.String
+}
+
+type RequestEditBook struct {
+	Slot    proto.VarInt
+	Entries proto.Array[proto.String]
+	Title   proto.Optional[proto.String]
+}
+
+type RequestSelectTrade struct {
+	Slot proto.VarInt
+}
+
+type RequestSetHeldItem struct {
+	Slot proto.Short
+}
+
+type RequestSetPlayerPositionAndRotation struct {
+	X, FeetY, Z proto.Double
+	Yaw         proto.Float
+	Pitch       proto.Float
+	OnGround    proto.Bool
+}
+
 func (c *Client) PerformRespawn() error {
 	packet := proto.NewPacket(0x07)
 	if err := packet.Append(&RequestRespawn{TypeID: proto.VarInt(0)}); err != nil {
@@ -22,7 +201,7 @@ func (c *Client) PerformRespawn() error {
 	if err := c.SendPacket(packet); err != nil {
 		return err
 	}
-	log.Printf("[INFO] Player '%s' performed respawn\n", c.Player.Name)
+	log.Printf("[INFO] Player '%s' performed respawn\n", c.Player.Name())
 	return nil
 }
 
@@ -38,3 +217,177 @@ func (c *Client) SendAlivePacket(unique proto.Long) error {
 
 	return nil
 }
+
+func (c *Client) SendConfirmTeleportation(teleportID proto.VarInt) error {
+	packet := proto.NewPacket(0x00)
+	if err := packet.Append(&RequestConfirmTeleportation{TeleportID: teleportID}); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) SendPositionAndRotation(x, y, z float64, yaw, pitch float32, onGround bool) error {
+	packet := proto.NewPacket(0x15)
+	err := packet.Append(&RequestSetPlayerPositionAndRotation{
+		X:        proto.Double(x),
+		FeetY:    proto.Double(y),
+		Z:        proto.Double(z),
+		Yaw:      proto.Float(yaw),
+		Pitch:    proto.Float(pitch),
+		OnGround: proto.Bool(onGround),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) SendPosition(x, y, z float64, onGround bool) error {
+	packet := proto.NewPacket(0x14)
+	err := packet.Append(&RequestSetPlayerPosition{
+		X:        proto.Double(x),
+		FeetY:    proto.Double(y),
+		Z:        proto.Double(z),
+		OnGround: proto.Bool(onGround),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) SendRotation(yaw, pitch float32, onGround bool) error {
+	packet := proto.NewPacket(0x16)
+	err := packet.Append(&RequestSetPlayerRotation{
+		Yaw:      proto.Float(yaw),
+		Pitch:    proto.Float(pitch),
+		OnGround: proto.Bool(onGround),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) SendOnGround(onGround bool) error {
+	packet := proto.NewPacket(0x17)
+	if err := packet.Append(&RequestSetPlayerOnGround{OnGround: proto.Bool(onGround)}); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) SendPlayerCommand(action proto.VarInt) error {
+	packet := proto.NewPacket(0x1e)
+	err := packet.Append(&RequestPlayerCommand{
+		EntityID: proto.VarInt(c.Player.EntityID()),
+		ActionID: action,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// nextSequence returns the sequence number for the next block interaction.
+// The server acknowledges it once the interaction is processed.
+func (c *Client) nextSequence() proto.VarInt {
+	return proto.VarInt(atomic.AddInt32(&c.sequence, 1))
+}
+
+// useItemOn clicks face of the block at pos with the item held in hand.
+// Cursor is the clicked point relative to the block origin. It returns the
+// sequence number the server acknowledges the click with.
+func (c *Client) useItemOn(hand proto.VarInt, pos proto.Position, face proto.Direction, cursorX, cursorY, cursorZ float32) (proto.VarInt, error) {
+	sequence := c.nextSequence()
+	packet := proto.NewPacket(0x31)
+	err := packet.Append(&RequestUseItemOn{
+		Hand:     hand,
+		Location: pos,
+		Face:     proto.VarInt(face),
+		CursorX:  proto.Float(cursorX),
+		CursorY:  proto.Float(cursorY),
+		CursorZ:  proto.Float(cursorZ),
+		Sequence: sequence,
+	})
+	if err != nil {
+		return sequence, err
+	}
+
+	return sequence, c.SendPacket(packet)
+}
+
+// SendPlayerAction sends Player Action and returns the sequence number the
+// server acknowledges it with.
+func (c *Client) SendPlayerAction(status proto.VarInt, pos proto.Position, face proto.Direction) (proto.VarInt, error) {
+	sequence := c.nextSequence()
+	packet := proto.NewPacket(0x1d)
+	err := packet.Append(&RequestPlayerAction{
+		Status:   status,
+		Location: pos,
+		Face:     proto.Byte(face),
+		Sequence: sequence,
+	})
+	if err != nil {
+		return sequence, err
+	}
+
+	return sequence, c.SendPacket(packet)
+}
+
+// UseItem uses the item held in hand like a right click in the air, e.g.
+// eating, drawing a bow or raising a shield. Items used over time are used
+// until they are finished or ReleaseUseItem is called.
+func (c *Client) UseItem(hand proto.VarInt) error {
+	packet := proto.NewPacket(0x32)
+	if err := packet.Append(&RequestUseItem{Hand: hand, Sequence: c.nextSequence()}); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// ReleaseUseItem stops using the held item, e.g. shoots a drawn bow or
+// lowers a shield.
+func (c *Client) ReleaseUseItem() error {
+	_, err := c.SendPlayerAction(ActionReleaseUseItem, proto.Position{}, proto.DirectionDown)
+	return err
+}
+
+func (c *Client) SwingArm(hand proto.VarInt) error {
+	packet := proto.NewPacket(0x2f)
+	if err := packet.Append(&RequestSwingArm{Hand: hand}); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// sendInteract sends Interact. Target is the clicked point relative to the
+// entity and only sent with InteractTypeAt, hand is not sent for attacks.
+func (c *Client) sendInteract(entityID int32, kind proto.VarInt, target *[3]float32, hand proto.VarInt) error {
+	id := proto.VarInt(entityID)
+	values := []any{&id, &kind}
+	if kind == InteractTypeAt && target != nil {
+		x, y, z := proto.Float(target[0]), proto.Float(target[1]), proto.Float(target[2])
+		values = append(values, &x, &y, &z)
+	}
+	if kind != InteractTypeAttack {
+		values = append(values, &hand)
+	}
+	sneaking := proto.Bool(c.Player.State().sneaking())
+	values = append(values, &sneaking)
+
+	packet := proto.NewPacket(0x10)
+	if err := packet.Append(values...); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
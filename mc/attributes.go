@@ -0,0 +1,198 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"time"
+)
+
+// Attribute keys used by the vanilla server.
+const (
+	AttrMaxHealth           = "minecraft:generic.max_health"
+	AttrFollowRange         = "minecraft:generic.follow_range"
+	AttrKnockbackResistance = "minecraft:generic.knockback_resistance"
+	AttrMovementSpeed       = "minecraft:generic.movement_speed"
+	AttrFlyingSpeed         = "minecraft:generic.flying_speed"
+	AttrAttackDamage        = "minecraft:generic.attack_damage"
+	AttrAttackKnockback     = "minecraft:generic.attack_knockback"
+	AttrAttackSpeed         = "minecraft:generic.attack_speed"
+	AttrArmor               = "minecraft:generic.armor"
+	AttrArmorToughness      = "minecraft:generic.armor_toughness"
+	AttrLuck                = "minecraft:generic.luck"
+)
+
+// Attribute modifier operations.
+const (
+	ModifierAdd        = 0
+	ModifierAddPercent = 1
+	ModifierMultiply   = 2
+)
+
+type AttributeModifier struct {
+	UUID      proto.Uuid
+	Amount    float64
+	Operation byte
+}
+
+type Attribute struct {
+	Key       string
+	Base      float64
+	Modifiers []AttributeModifier
+}
+
+// Value applies the modifiers to the base value the same way the vanilla
+// game does: additions first, then percentages of the sum, then multipliers.
+func (a Attribute) Value() float64 {
+	base := a.Base
+	for _, m := range a.Modifiers {
+		if m.Operation == ModifierAdd {
+			base += m.Amount
+		}
+	}
+
+	value := base
+	for _, m := range a.Modifiers {
+		if m.Operation == ModifierAddPercent {
+			value += base * m.Amount
+		}
+	}
+	for _, m := range a.Modifiers {
+		if m.Operation == ModifierMultiply {
+			value *= 1 + m.Amount
+		}
+	}
+	return value
+}
+
+// Status effect IDs, see https://wiki.vg/index.php?title=Protocol&oldid=18375#Entity_Effect
+const (
+	EffectSpeed = iota + 1
+	EffectSlowness
+	EffectHaste
+	EffectMiningFatigue
+	EffectStrength
+	EffectInstantHealth
+	EffectInstantDamage
+	EffectJumpBoost
+	EffectNausea
+	EffectRegeneration
+	EffectResistance
+	EffectFireResistance
+	EffectWaterBreathing
+	EffectInvisibility
+	EffectBlindness
+	EffectNightVision
+	EffectHunger
+	EffectWeakness
+	EffectPoison
+	EffectWither
+	EffectHealthBoost
+	EffectAbsorption
+	EffectSaturation
+	EffectGlowing
+	EffectLevitation
+	EffectLuck
+	EffectUnluck
+	EffectSlowFalling
+	EffectConduitPower
+	EffectDolphinsGrace
+	EffectBadOmen
+	EffectHeroOfTheVillage
+	EffectDarkness
+)
+
+// Bits of Effect.Flags.
+const (
+	EffectFlagAmbient       = 0x01
+	EffectFlagShowParticles = 0x02
+	EffectFlagShowIcon      = 0x04
+)
+
+type Effect struct {
+	ID        int32
+	Amplifier int8  // Amplifier is the effect level minus one
+	Duration  int32 // Duration in ticks when the effect was applied, -1 for infinity
+	Flags     byte
+	Applied   time.Time
+}
+
+// Expired reports whether the effect ran out according to the local clock.
+// The server still sends Remove Entity Effect, this only helps with gaps.
+func (e Effect) Expired(now time.Time) bool {
+	if e.Duration < 0 {
+		return false
+	}
+	return now.Sub(e.Applied) >= time.Duration(e.Duration)*50*time.Millisecond
+}
+
+// Attribute returns the current value of the attribute with given key.
+func (e *Entity) Attribute(key string) (float64, bool) {
+	attr, ok := e.Attributes[key]
+	if !ok {
+		return 0, false
+	}
+	return attr.Value(), true
+}
+
+// Effect returns the active effect with given ID.
+func (e *Entity) Effect(id int32) (Effect, bool) {
+	effect, ok := e.Effects[id]
+	return effect, ok
+}
+
+// PlayerEntity returns the entity of the logged-in player, which holds its
+// attributes and effects.
+func (c *Client) PlayerEntity() (Entity, bool) {
+	return c.Entities.Get(c.Player.EntityID())
+}
+
+func (c *Client) handleUpdateAttributes(pk proto.Packet) error {
+	var update proto.UpdateAttributesResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(update.EntityID), func(entity *Entity) {
+		for _, prop := range update.Properties {
+			attr := Attribute{Key: string(prop.Key), Base: float64(prop.Value)}
+			for _, m := range prop.Modifiers {
+				attr.Modifiers = append(attr.Modifiers, AttributeModifier{
+					UUID:      m.UUID,
+					Amount:    float64(m.Amount),
+					Operation: byte(m.Operation),
+				})
+			}
+			entity.Attributes[attr.Key] = attr
+		}
+	})
+	return nil
+}
+
+func (c *Client) handleEntityEffect(pk proto.Packet) error {
+	var effect proto.EntityEffectResponse
+	if err := pk.Scan(&effect); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(effect.EntityID), func(entity *Entity) {
+		entity.Effects[int32(effect.EffectID)] = Effect{
+			ID:        int32(effect.EffectID),
+			Amplifier: int8(effect.Amplifier),
+			Duration:  int32(effect.Duration),
+			Flags:     byte(effect.Flags),
+			Applied:   time.Now(),
+		}
+	})
+	return nil
+}
+
+func (c *Client) handleRemoveEntityEffect(pk proto.Packet) error {
+	var effect proto.RemoveEntityEffectResponse
+	if err := pk.Scan(&effect); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(effect.EntityID), func(entity *Entity) {
+		delete(entity.Effects, int32(effect.EffectID))
+	})
+	return nil
+}
@@ -0,0 +1,62 @@
+package mc
+
+import (
+	"fmt"
+	"mc-bot/mc/proto"
+)
+
+// Limits of Edit Book enforced by the vanilla server.
+const (
+	maxBookPages      = 100
+	maxBookPageLength = 8192
+	// maxBookTitle is the longest title of a valid written book, the packet
+	// allows 128 but longer titles make the book invalid
+	maxBookTitle = 32
+)
+
+// WriteBook replaces the pages of the book and quill in hotbar slot 0-8 or
+// HotbarOffhand. A non-empty title signs the book, turning it into
+// a written book that cannot be edited anymore.
+func (c *Client) WriteBook(hotbar int, pages []string, title string) error {
+	slot := SlotHotbar + hotbar
+	switch {
+	case hotbar == HotbarOffhand:
+		slot = SlotOffhand
+	case hotbar < 0 || hotbar > 8:
+		return fmt.Errorf("invalid hotbar slot %d", hotbar)
+	}
+
+	book := c.Inventory.Slot(slot)
+	if book.Empty() {
+		return ErrSlotEmpty
+	}
+	if name := c.items().Item(int32(book.ItemID)).Name; name != "" && name != "minecraft:writable_book" {
+		return ErrNotWritableBook
+	}
+
+	if len(pages) > maxBookPages {
+		return fmt.Errorf("book has at most %d pages, got %d", maxBookPages, len(pages))
+	}
+	entries := make(proto.Array[proto.String], len(pages))
+	for i, page := range pages {
+		if textLength(page) > maxBookPageLength {
+			return fmt.Errorf("book page %d is longer than %d characters", i+1, maxBookPageLength)
+		}
+		entries[i] = proto.String(page)
+	}
+	if textLength(title) > maxBookTitle {
+		return fmt.Errorf("book title is longer than %d characters", maxBookTitle)
+	}
+
+	request := RequestEditBook{Slot: proto.VarInt(hotbar), Entries: entries}
+	if title != "" {
+		request.Title = proto.Optional[proto.String]{Present: true, Value: proto.String(title)}
+	}
+
+	packet := proto.NewPacket(0x0e)
+	if err := packet.Append(&request); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
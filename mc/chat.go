@@ -0,0 +1,313 @@
+package mc
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mc-bot/mc/proto"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxChatLength is the longest chat message or command the server accepts.
+	maxChatLength = 256
+	// maxPendingAcks is how many seen messages may pile up before they are
+	// acknowledged without sending a message.
+	maxPendingAcks = 64
+)
+
+var ErrChatTooLong = errors.New("chat message is longer than 256 characters")
+
+type ChatKind byte
+
+const (
+	ChatPlayer    ChatKind = iota // ChatPlayer is a message sent by a player
+	ChatSystem                    // ChatSystem is a message from the server or a command
+	ChatDisguised                 // ChatDisguised is a player message sent without a signature, e.g. by /say from the console
+)
+
+// ChatEvent is emitted for every received chat message.
+type ChatEvent struct {
+	Kind ChatKind
+
+	// Sender, Message, Timestamp and Signature are only set for ChatPlayer.
+	Sender    proto.Uuid
+	Message   string // Message is the plain text typed by the sender
+	Timestamp time.Time
+	Signature *proto.Signature // Signature is nil for unsigned messages
+	Verified  bool             // Verified is set when Signature matches the chat session of Sender
+
+	// Content is the message as a text component. For player messages it is
+	// the content modified by the server, if any.
+	Content    proto.Chat
+	SenderName proto.Chat
+	TargetName proto.Chat // TargetName is set for direct and team messages
+	ChatType   ChatType   // ChatType formats player and disguised messages
+	Overlay    bool       // Overlay is set for system messages shown above the hotbar
+}
+
+// lastSeen tracks signed messages received from other players. Every sent
+// message acknowledges them, which the server checks even for unsigned
+// messages. It follows the vanilla LastSeenMessagesTracker.
+type lastSeen struct {
+	mu      sync.Mutex
+	entries [proto.LastSeenMessages]*proto.Signature
+	tail    int
+	offset  int // offset is the number of messages seen since the last acknowledgement
+	last    *proto.Signature
+}
+
+// add records a received signed message and returns the number of messages
+// waiting for an acknowledgement.
+func (l *lastSeen) add(signature proto.Signature) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last != nil && *l.last == signature {
+		return l.offset
+	}
+	l.last = &signature
+	l.entries[l.tail] = &signature
+	l.tail = (l.tail + 1) % len(l.entries)
+	l.offset++
+	return l.offset
+}
+
+// acknowledge returns the number of new messages and the set of tracked
+// messages to send with a chat packet. signatures are the tracked messages
+// from the oldest, which signed messages include.
+func (l *lastSeen) acknowledge() (count int, bits proto.LastSeenBits, signatures []proto.Signature) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count, l.offset = l.offset, 0
+	for i := range l.entries {
+		if entry := l.entries[(l.tail+i)%len(l.entries)]; entry != nil {
+			bits.Set(i)
+			signatures = append(signatures, *entry)
+		}
+	}
+	return count, bits, signatures
+}
+
+// takeOffset returns and clears the number of messages since the last
+// acknowledgement.
+func (l *lastSeen) takeOffset() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.offset
+	l.offset = 0
+	return count
+}
+
+// SendChat sends a chat message. Messages starting with '/' are sent as
+// commands.
+func (c *Client) SendChat(message string) error {
+	if strings.HasPrefix(message, "/") {
+		return c.SendCommand(message)
+	}
+	return c.sendChatMessage(message)
+}
+
+// sendChatMessage sends message as chat even when it starts with '/', the
+// server never runs commands from chat messages.
+func (c *Client) sendChatMessage(message string) error {
+	if textLength(message) > maxChatLength {
+		return ErrChatTooLong
+	}
+
+	count, bits, lastSeen := c.lastSeen.acknowledge()
+	request := RequestChatMessage{
+		Message:      proto.String(message),
+		Timestamp:    proto.Long(time.Now().UnixMilli()),
+		Salt:         proto.Long(chatSalt()),
+		MessageCount: proto.VarInt(count),
+		Acknowledged: bits,
+	}
+
+	signature, err := c.signMessage(request.Salt, request.Timestamp, message, lastSeen)
+	if err != nil {
+		return err
+	}
+	if signature != nil {
+		request.Signature = proto.Optional[proto.Signature]{Present: true, Value: *signature}
+	}
+
+	packet := proto.NewPacket(0x05)
+	if err := packet.Append(&request); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// textLength returns the length of s as the server counts it, in UTF-16
+// code units of a Java string.
+func textLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if r >= 0x10000 {
+			n++ // surrogate pair
+		}
+	}
+	return n
+}
+
+// signMessage signs a message when a chat session was started with
+// Client.Keys and returns nil otherwise.
+func (c *Client) signMessage(salt, timestamp proto.Long, message string, lastSeen []proto.Signature) (*proto.Signature, error) {
+	if !c.signer.active() {
+		return nil, nil
+	}
+
+	sender, err := parseUUID(c.Player.UUID())
+	if err != nil {
+		return nil, err
+	}
+	return c.signer.sign(sender, int64(salt), time.UnixMilli(int64(timestamp)), message, lastSeen)
+}
+
+// SendCommand runs a command, with or without the leading '/'. When chat is
+// signed, message arguments of commands like /msg are signed too; this needs
+// the command graph to find them.
+func (c *Client) SendCommand(command string) error {
+	command = strings.TrimPrefix(command, "/")
+	if textLength(command) > maxChatLength {
+		return ErrChatTooLong
+	}
+
+	count, bits, lastSeen := c.lastSeen.acknowledge()
+	request := RequestChatCommand{
+		Command:      proto.String(command),
+		Timestamp:    proto.Long(time.Now().UnixMilli()),
+		Salt:         proto.Long(chatSalt()),
+		MessageCount: proto.VarInt(count),
+		Acknowledged: bits,
+	}
+
+	// unknown commands are sent as they are and rejected by the server
+	if parsed, err := c.Commands.Parse(command); err == nil {
+		for _, arg := range parsed.Arguments {
+			if arg.Parser != "minecraft:message" {
+				continue
+			}
+
+			signature, err := c.signMessage(request.Salt, request.Timestamp, arg.Value, lastSeen)
+			if err != nil {
+				return err
+			}
+			if signature != nil {
+				request.ArgumentSignatures = append(request.ArgumentSignatures, ArgumentSignature{
+					Name:      proto.String(arg.Name),
+					Signature: *signature,
+				})
+			}
+		}
+	}
+
+	packet := proto.NewPacket(0x04)
+	if err := packet.Append(&request); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) sendMessageAcknowledgment(count int) error {
+	packet := proto.NewPacket(0x03)
+	if err := packet.Append(&RequestMessageAcknowledgment{MessageCount: proto.VarInt(count)}); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// parseUUID parses a UUID with or without dashes.
+func parseUUID(s string) (proto.Uuid, error) {
+	data, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(data) != 16 {
+		return proto.Uuid{}, fmt.Errorf("invalid player UUID %q", s)
+	}
+	return *proto.NewUuidFromBytes(data), nil
+}
+
+func chatSalt() int64 {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return int64(binary.BigEndian.Uint64(buf[:]))
+}
+
+func (c *Client) handlePlayerChat(pk proto.Packet) error {
+	var chat proto.PlayerChatResponse
+	if err := pk.Scan(&chat); err != nil {
+		return err
+	}
+
+	chatType, _ := c.Registries.ChatType(int32(chat.ChatType))
+	event := ChatEvent{
+		Kind:       ChatPlayer,
+		Sender:     chat.Sender,
+		Message:    string(chat.Message),
+		Timestamp:  time.UnixMilli(int64(chat.Timestamp)),
+		Content:    chat.UnsignedContent.Value,
+		SenderName: chat.SenderName,
+		TargetName: chat.TargetName.Value,
+		ChatType:   chatType,
+	}
+	if !chat.UnsignedContent.Present {
+		event.Content = proto.TextChat(string(chat.Message))
+	}
+
+	if chat.Signature.Present {
+		event.Signature = &chat.Signature.Value
+		if lastSeen, ok := c.signatures.unpack(chat.PreviousMessages); ok {
+			event.Verified = c.verify(chat, lastSeen)
+			c.signatures.push(lastSeen, event.Signature)
+		}
+
+		if c.lastSeen.add(chat.Signature.Value) > maxPendingAcks {
+			if err := c.sendMessageAcknowledgment(c.lastSeen.takeOffset()); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.emit(event)
+	return nil
+}
+
+func (c *Client) handleSystemChat(pk proto.Packet) error {
+	var chat proto.SystemChatResponse
+	if err := pk.Scan(&chat); err != nil {
+		return err
+	}
+
+	c.emit(ChatEvent{Kind: ChatSystem, Content: chat.Content, Overlay: bool(chat.Overlay)})
+	if chat.Overlay {
+		c.setActionBar(chat.Content)
+	}
+	return nil
+}
+
+func (c *Client) handleDisguisedChat(pk proto.Packet) error {
+	var chat proto.DisguisedChatResponse
+	if err := pk.Scan(&chat); err != nil {
+		return err
+	}
+
+	chatType, _ := c.Registries.ChatType(int32(chat.ChatType))
+	c.emit(ChatEvent{
+		Kind:       ChatDisguised,
+		Content:    chat.Message,
+		SenderName: chat.SenderName,
+		TargetName: chat.TargetName.Value,
+		ChatType:   chatType,
+	})
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type ConnectionState string
@@ -25,10 +26,60 @@ type Client struct {
 	State             ConnectionState
 	compressThreshold int
 	Player            Player
+	Entities          *Entities
+	World             *World
+	Inventory         *Inventory
+	Recipes           *Recipes
+	Tags              *Tags
+	Registries        *Registries
+	Commands          *Commands
+	Players           *PlayerList
+	Deaths            *DeathLog
+	Scoreboard        *Scoreboard
+	HUD               *HUD
+	Keys              KeySource          // Keys signs chat messages, chat is unsigned when nil
+	Blocks            BlockRegistry      // Blocks describes block states, features needing them fail with ErrNoBlockRegistry when nil
+	Items             ItemRegistry       // Items describes item IDs, DefaultItems is used when nil and eating needs a real one
+	EntityTypes       EntityTypeRegistry // EntityTypes describes entity types, DefaultEntityTypes is used when nil and Guard needs a real one
+	OnDeath           DeathPolicy        // OnDeath is what the client does when the player dies
+
+	writeMu     sync.Mutex
+	physics     *physics
+	events      *events
+	sequence    int32
+	acks        *sequenceAcks
+	combat      combat
+	death       deathState
+	signEditor  signEditor
+	merchant    merchant
+	lastSeen    lastSeen
+	signer      chatSigner
+	sessions    chatSessions
+	signatures  signatureCache
+	suggestions suggestionRequests
 }
 
 func NewClient(version int) Client {
-	return Client{Conn: nil, State: ConnStateUnknown, compressThreshold: -1, Version: version}
+	return Client{
+		Conn:              nil,
+		State:             ConnStateUnknown,
+		compressThreshold: -1,
+		Version:           version,
+		Entities:          newEntities(),
+		World:             newWorld(),
+		Inventory:         newInventory(),
+		Recipes:           newRecipes(),
+		Tags:              newTags(),
+		Registries:        newRegistries(),
+		Commands:          &Commands{},
+		Players:           newPlayerList(),
+		Deaths:            &DeathLog{},
+		Scoreboard:        newScoreboard(),
+		HUD:               newHUD(),
+		physics:           &physics{},
+		events:            newEvents(),
+		acks:              newSequenceAcks(),
+	}
 }
 
 func (c *Client) Connect(server Server) error {
@@ -38,6 +89,9 @@ func (c *Client) Connect(server Server) error {
 		return fmt.Errorf("cannot connect to server: %w", err)
 	}
 
+	c.Inventory.mu.Lock()
+	c.Inventory.client = c
+	c.Inventory.mu.Unlock()
 	return nil
 }
 
@@ -49,6 +103,9 @@ func (c *Client) SendPacket(pk proto.Packet) error {
 		data = pk.CompressBytes(c.compressThreshold)
 	}
 
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
 	_, err := c.Conn.Write(data)
 	return err
 }
@@ -112,7 +169,7 @@ func (c *Client) ServerStatus() {
 }
 
 func (c *Client) Login(name, uuid string) error {
-	c.Player.Name, c.Player.UUID = name, uuid
+	c.Player.setProfile(name, uuid)
 	if err := c.Handshake(ConnStateLogin); err != nil {
 		return fmt.Errorf("cannot establish handshake: %w", err)
 	}
@@ -122,7 +179,7 @@ func (c *Client) Login(name, uuid string) error {
 		return fmt.Errorf("cannot append login start request data: %w", err)
 	}
 
-	if len(uuid) != 0 {
+	if len(uuid) == 0 {
 		if err := pk.Append(proto.NewBool(false)); err != nil {
 			return fmt.Errorf("cannot append login start request data: %w", err)
 		}
@@ -185,7 +242,8 @@ func (c *Client) handlePlayStateResponses(pk proto.Packet) error {
 	switch pk.ID {
 
 	case 0x28:
-	// https://wiki.vg/Protocol#Login_(play)
+		// https://wiki.vg/Protocol#Login_(play)
+		return c.handleLoginPlay(pk)
 	case 0x6b:
 	// https://wiki.vg/Protocol#Feature_Flags
 	case 0x17:
@@ -193,28 +251,57 @@ func (c *Client) handlePlayStateResponses(pk proto.Packet) error {
 	case 0x0c:
 	// https://wiki.vg/Protocol#Change_Difficulty
 	case 0x34:
-	// https://wiki.vg/Protocol#Player_Abilities
+		// https://wiki.vg/Protocol#Player_Abilities
+		return c.handlePlayerAbilities(pk)
 	case 0x4d:
-	// https://wiki.vg/Protocol#Set_Held_Item
+		// https://wiki.vg/Protocol#Set_Held_Item
+		return c.handleSetHeldItem(pk)
 	case 0x6d:
-	// https://wiki.vg/Protocol#Update_Recipes
+		// https://wiki.vg/Protocol#Update_Recipes
+		return c.handleUpdateRecipes(pk)
 	case 0x6e:
-	// https://wiki.vg/Protocol#Update_Tags
+		// https://wiki.vg/Protocol#Update_Tags
+		return c.handleUpdateTags(pk)
 	case 0x1c:
 	// https://wiki.vg/Protocol#Entity_Event
 	case 0x10:
-	// https://wiki.vg/Protocol#Commands
+		// https://wiki.vg/Protocol#Commands
+		return c.handleCommands(pk)
+	case 0x0f:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Command_Suggestions_Response
+		return c.handleCommandSuggestions(pk)
 	case 0x3d:
-	// https://wiki.vg/Protocol#Update_Recipe_Book
+		// https://wiki.vg/Protocol#Update_Recipe_Book
+		return c.handleUpdateRecipeBook(pk)
 	case 0x3c:
-	// https://wiki.vg/Protocol#Synchronize_Player_Position
+		// https://wiki.vg/Protocol#Synchronize_Player_Position
+		return c.handleSyncPlayerPosition(pk)
 	case 0x45:
 	// https://wiki.vg/Protocol#Server_Data
+	case 0x30:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Open_Screen
+		return c.handleOpenScreen(pk)
+	case 0x11:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Close_Container
+		return c.handleCloseContainer(pk)
+	case 0x13:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Container_Property
+		return c.handleSetContainerProperty(pk)
+	case 0x14:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Container_Slot
+		return c.handleSetContainerSlot(pk)
+	case 0x06:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Acknowledge_Block_Change
+		return c.handleAcknowledgeBlockChange(pk)
 	case 0x24:
-	// https://wiki.vg/Protocol#Chunk_Data_and_Update_Light
+		// https://wiki.vg/Protocol#Chunk_Data_and_Update_Light
+		return c.handleChunkData(pk)
+	case 0x1e:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Unload_Chunk
+		return c.handleUnloadChunk(pk)
 	case 0x57:
 		// https://wiki.vg/Protocol#Set_Health
-		c.handleSetHealthPacket(pk)
+		return c.handleSetHealthPacket(pk)
 	case 0x1a:
 		// https://wiki.vg/Protocol#Disconnect_(play)
 		c.handleDisconnect(pk)
@@ -228,40 +315,108 @@ func (c *Client) handlePlayStateResponses(pk proto.Packet) error {
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Sound_Effect
 	case 0x42:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Head_Rotation
+		return c.handleSetHeadRotation(pk)
 	case 0x2c:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Entity_Position_and_Rotation
+		return c.handleUpdateEntityPositionAndRotation(pk)
 	case 0x54:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Entity_Velocity
+		return c.handleSetEntityVelocity(pk)
 	case 0x2b:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Entity_Position
+		return c.handleUpdateEntityPosition(pk)
 	case 0x27:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Light
 	case 0x68:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Teleport_Entity
+		return c.handleTeleportEntity(pk)
 	case 0x43:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Section_Blocks
+		return c.handleUpdateSectionBlocks(pk)
 	case 0x0a:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Block_Update
+		return c.handleBlockUpdate(pk)
+	case 0x08:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Block_Entity_Data
+		return c.handleBlockEntityData(pk)
+	case 0x31:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Open_Sign_Editor
+		return c.handleOpenSignEditor(pk)
+	case 0x2a:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Merchant_Offers
+		return c.handleMerchantOffers(pk)
+	case 0x51:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Display_Objective
+		return c.handleDisplayObjective(pk)
+	case 0x58:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Objectives
+		return c.handleUpdateObjectives(pk)
+	case 0x5a:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Teams
+		return c.handleUpdateTeams(pk)
+	case 0x5b:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Score
+		return c.handleUpdateScore(pk)
+	case 0x0b:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Boss_Bar
+		return c.handleBossBar(pk)
+	case 0x5f:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Title_Text
+		return c.handleSetTitleText(pk)
+	case 0x5d:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Subtitle_Text
+		return c.handleSetSubtitleText(pk)
+	case 0x60:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Title_Animation_Times
+		return c.handleSetTitleAnimationTimes(pk)
+	case 0x0e:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Clear_Titles
+		return c.handleClearTitles(pk)
+	case 0x46:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Action_Bar_Text
+		return c.handleSetActionBarText(pk)
+	case 0x65:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Tab_List_Header_And_Footer
+		return c.handleSetTabListHeaderAndFooter(pk)
 	case 0x6a:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Attributes
+		return c.handleUpdateAttributes(pk)
+	case 0x6c:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Entity_Effect
+		return c.handleEntityEffect(pk)
+	case 0x3f:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Remove_Entity_Effect
+		return c.handleRemoveEntityEffect(pk)
 	case 0x00:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Bundle_Delimiter
 	case 0x01:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Spawn_Entity
+		return c.handleSpawnEntity(pk)
+	case 0x02:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Spawn_Experience_Orb
+		return c.handleSpawnExperienceOrb(pk)
+	case 0x03:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Spawn_Player
+		return c.handleSpawnPlayer(pk)
 	case 0x52:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Entity_Metadata
+		return c.handleSetEntityMetadata(pk)
 	case 0x2d:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Entity_Rotation
+		return c.handleUpdateEntityRotation(pk)
 	case 0x3e:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Remove_Entities
+		return c.handleRemoveEntities(pk)
 	case 0x5e:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Time
 	case 0x56:
-	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Experience
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Experience
+		return c.handleSetExperience(pk)
 	case 0x69:
 	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Advancements
 	case 0x12:
-	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Container_Content
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_Container_Content
+		return c.handleSetContainerContent(pk)
 	case 0x55:
 	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Set_center_Chunk
 	case 0x4e:
@@ -271,15 +426,28 @@ func (c *Client) handlePlayStateResponses(pk proto.Packet) error {
 	case 0x22:
 	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Initialize_World_Border
 	case 0x3a:
-	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Info_Update
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Info_Update
+		return c.handlePlayerInfoUpdate(pk)
+	case 0x39:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Info_Remove
+		return c.handlePlayerInfoRemove(pk)
 	case 0x25:
 	// https://wiki.vg/index.php?title=Protocol&oldid=18375#World_Event
 	case 0x18:
-	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Damage_Event
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Damage_Event
+		return c.handleDamageEvent(pk)
 	case 0x41:
-	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Respawn
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Respawn
+		return c.handleRespawn(pk)
 	case 0x64:
-	// https://wiki.vg/index.php?title=Protocol&oldid=18375#Respawn#System_Chat_Message
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#System_Chat_Message
+		return c.handleSystemChat(pk)
+	case 0x35:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Chat_Message
+		return c.handlePlayerChat(pk)
+	case 0x1b:
+		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Disguised_Chat_Message
+		return c.handleDisguisedChat(pk)
 	case 0x1f:
 		// https://wiki.vg/index.php?title=Protocol&oldid=18375#Game_Event
 		return c.handleGameEvent(pk)
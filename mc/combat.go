@@ -0,0 +1,237 @@
+package mc
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// attackReachSurvival and attackReachCreative are the entity interaction
+	// ranges measured from the eyes to the hitbox.
+	attackReachSurvival = 3.0
+	attackReachCreative = 6.0
+	// defaultAttackSpeed is the attack speed of an empty hand.
+	defaultAttackSpeed = 4.0
+	// critJumpTicks limits waiting for the player to leave the ground.
+	critJumpTicks = 10
+)
+
+// combat tracks the attack cooldown, which the game resets on every swing
+// and when the held item changes.
+type combat struct {
+	mu         sync.Mutex
+	lastAttack time.Time
+}
+
+func (c *combat) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastAttack = time.Now()
+}
+
+func (c *combat) since() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Since(c.lastAttack)
+}
+
+// AttackStrength returns how much of the attack cooldown has passed, from
+// 0 right after an attack to 1 when attacks deal full damage. It follows the
+// vanilla Player#getAttackStrengthScale.
+func (c *Client) AttackStrength() float64 {
+	speed := defaultAttackSpeed
+	if entity, ok := c.PlayerEntity(); ok {
+		if v, ok := entity.Attribute(AttrAttackSpeed); ok && v > 0 {
+			speed = v
+		}
+	}
+
+	period := 20 / speed
+	ticks := float64(c.combat.since()) / float64(TickDuration)
+	return math.Max(0, math.Min(1, (ticks+0.5)/period))
+}
+
+// WaitAttackReady blocks until the attack cooldown has passed.
+func (c *Client) WaitAttackReady(ctx context.Context) error {
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+
+	for c.AttackStrength() < 1 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// Attack looks at the entity, hits it with the held item and swings the
+// main hand. It does not wait for the cooldown, attacks before
+// WaitAttackReady returns deal less damage.
+func (c *Client) Attack(entityID int32) error {
+	entity, ok := c.Entities.Get(entityID)
+	if !ok {
+		return ErrUnknownEntity
+	}
+
+	box := entity.Box(c.entityTypes().EntityType(entity.Type))
+	if !c.entityInReach(box) {
+		return ErrOutOfReach
+	}
+
+	c.LookAt(entity.X, (box.MinY+box.MaxY)/2, entity.Z)
+	if err := c.sendLook(); err != nil {
+		return err
+	}
+
+	if err := c.sendInteract(entityID, InteractTypeAttack, nil, HandMain); err != nil {
+		return err
+	}
+	c.combat.reset()
+	return c.SwingArm(HandMain)
+}
+
+// CriticalAttack jumps and attacks the entity while falling, which the
+// server counts as a critical hit. It needs RunPhysics and falls back to a
+// normal attack when the player cannot jump.
+func (c *Client) CriticalAttack(ctx context.Context, entityID int32) error {
+	controls := c.Controls()
+	defer c.SetControls(controls)
+
+	// sprinting prevents critical hits
+	jump := controls
+	jump.Jump, jump.Sprint = true, false
+	c.SetControls(jump)
+
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+
+	for ticks := 0; ; ticks++ {
+		state := c.Player.State()
+		_, velY, _ := c.Velocity()
+		if !state.OnGround && velY < 0 {
+			break
+		}
+		if state.OnGround && ticks >= critJumpTicks {
+			break
+		}
+		if !state.OnGround {
+			jump.Jump = false
+			c.SetControls(jump)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return c.Attack(entityID)
+}
+
+// TargetFilter selects entities to attack.
+type TargetFilter func(entity Entity, kind EntityType) bool
+
+// HostileMobs selects monsters, see EntityType.Hostile.
+func HostileMobs(entity Entity, kind EntityType) bool {
+	return kind.Hostile
+}
+
+// SelectTarget returns the closest living entity within attack reach
+// accepted by filter.
+func (c *Client) SelectTarget(filter TargetFilter) (Entity, bool) {
+	state := c.Player.State()
+	var (
+		best     Entity
+		bestDist = math.Inf(1)
+	)
+	for _, entity := range c.Entities.All() {
+		if entity.ID == state.EntityID {
+			continue
+		}
+		if health, ok := entity.Health(); ok && health <= 0 {
+			continue
+		}
+
+		kind := c.entityTypes().EntityType(entity.Type)
+		box := entity.Box(kind)
+		if !c.entityInReach(box) || !filter(entity, kind) {
+			continue
+		}
+
+		if dist := eyeDistanceSq(state, box); dist < bestDist {
+			best, bestDist = entity, dist
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
+
+// GuardOptions configures Guard.
+type GuardOptions struct {
+	Filter TargetFilter // Filter selects targets, HostileMobs when nil
+	Crits  bool         // Crits makes every attack a jump attack
+}
+
+// Guard attacks targets within reach whenever the attack cooldown allows
+// until ctx is done. The default filter needs Client.EntityTypes to tell
+// monsters apart, DefaultEntityTypes knows none.
+func (c *Client) Guard(ctx context.Context, opts GuardOptions) error {
+	filter := opts.Filter
+	if filter == nil {
+		if c.EntityTypes == nil {
+			return ErrNoEntityTypeRegistry
+		}
+		filter = HostileMobs
+	}
+
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+
+	for {
+		if err := c.WaitAttackReady(ctx); err != nil {
+			return err
+		}
+
+		if target, ok := c.SelectTarget(filter); ok {
+			var err error
+			if opts.Crits {
+				err = c.CriticalAttack(ctx, target.ID)
+			} else {
+				err = c.Attack(target.ID)
+			}
+			// the target may have died or moved away in the meantime
+			if err != nil && err != ErrUnknownEntity && err != ErrOutOfReach {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) entityInReach(box AABB) bool {
+	state := c.Player.State()
+	reach := attackReachSurvival
+	if state.GameMode == GameModeCreative {
+		reach = attackReachCreative
+	}
+	return eyeDistanceSq(state, box) <= reach*reach
+}
+
+// eyeDistanceSq returns the squared distance from the eyes of the player to
+// the closest point of box.
+func eyeDistanceSq(state PlayerState, box AABB) float64 {
+	dx := math.Max(0, math.Max(box.MinX-state.X, state.X-box.MaxX))
+	dy := math.Max(0, math.Max(box.MinY-state.eyeY(), state.eyeY()-box.MaxY))
+	dz := math.Max(0, math.Max(box.MinZ-state.Z, state.Z-box.MaxZ))
+	return dx*dx + dy*dy + dz*dz
+}
@@ -0,0 +1,454 @@
+package mc
+
+import (
+	"context"
+	"fmt"
+	"mc-bot/mc/proto"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CommandNode is a node of the Brigadier command graph sent by the server.
+type CommandNode struct {
+	Type        byte   // Type is proto.NodeRoot, proto.NodeLiteral or proto.NodeArgument
+	Name        string // Name is the literal or the argument name
+	Executable  bool   // Executable is set when the command may end at this node
+	Children    []*CommandNode
+	Redirect    *CommandNode // Redirect continues parsing at another node, e.g. "execute run"
+	Parser      string       // Parser is the argument parser, e.g. "brigadier:integer"
+	Properties  any          // Properties of Parser, see proto.CommandNode
+	Suggestions string       // Suggestions names server-side suggestions, e.g. "minecraft:ask_server"
+}
+
+// next returns the nodes that may follow n.
+func (n *CommandNode) next() []*CommandNode {
+	if n.Redirect != nil && len(n.Children) == 0 {
+		return n.Redirect.Children
+	}
+	return n.Children
+}
+
+// Commands holds the commands the server lets the player run. It is safe for
+// concurrent use.
+type Commands struct {
+	mu   sync.RWMutex
+	root *CommandNode
+}
+
+// Root returns the root of the command graph or nil before the server sent
+// it.
+func (c *Commands) Root() *CommandNode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.root
+}
+
+// Available returns the names of all commands, sorted.
+func (c *Commands) Available() []string {
+	root := c.Root()
+	if root == nil {
+		return nil
+	}
+
+	var out []string
+	for _, child := range root.Children {
+		out = append(out, child.Name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ParsedArgument is an argument matched by Commands.Parse.
+type ParsedArgument struct {
+	Name   string
+	Parser string
+	Value  string
+	Start  int // Start is the index of Value in the command
+}
+
+type ParsedCommand struct {
+	Nodes     []*CommandNode // Nodes are the matched nodes in order
+	Arguments []ParsedArgument
+}
+
+// CommandSyntaxError reports where a command stopped matching the graph.
+type CommandSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *CommandSyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// Parse checks command, without the leading '/', against the command graph
+// the way Brigadier does. Literals and simple arguments are validated fully,
+// while arguments with a complex syntax like NBT or block states are only
+// checked for balanced brackets and quotes.
+func (c *Commands) Parse(command string) (*ParsedCommand, error) {
+	root := c.Root()
+	if root == nil {
+		return nil, &CommandSyntaxError{Msg: "command tree not received"}
+	}
+
+	parsed, err := parseCommand(root.Children, command, 0, &ParsedCommand{})
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// parseCommand matches one of nodes at pos and the rest of the command
+// after it, trying every possible branch.
+func parseCommand(nodes []*CommandNode, command string, pos int, parsed *ParsedCommand) (*ParsedCommand, *CommandSyntaxError) {
+	furthest := &CommandSyntaxError{Pos: pos, Msg: "unknown or incomplete command"}
+	fail := func(err *CommandSyntaxError) {
+		if err.Pos >= furthest.Pos {
+			furthest = err
+		}
+	}
+
+	// literals take precedence over arguments like in Brigadier
+	ordered := make([]*CommandNode, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Type == proto.NodeLiteral {
+			ordered = append(ordered, node)
+		}
+	}
+	for _, node := range nodes {
+		if node.Type == proto.NodeArgument {
+			ordered = append(ordered, node)
+		}
+	}
+
+	for _, node := range ordered {
+		var end int
+		next := &ParsedCommand{
+			Nodes:     append(append([]*CommandNode(nil), parsed.Nodes...), node),
+			Arguments: parsed.Arguments,
+		}
+
+		if node.Type == proto.NodeLiteral {
+			end = pos + len(node.Name)
+			if !strings.HasPrefix(command[pos:], node.Name) || (end < len(command) && command[end] != ' ') {
+				continue
+			}
+		} else {
+			var err error
+			if end, err = readArgument(node, command, pos); err != nil {
+				fail(&CommandSyntaxError{Pos: pos, Msg: fmt.Sprintf("invalid %s: %s", node.Name, err)})
+				continue
+			}
+			next.Arguments = append(append([]ParsedArgument(nil), parsed.Arguments...), ParsedArgument{
+				Name:   node.Name,
+				Parser: node.Parser,
+				Value:  command[pos:end],
+				Start:  pos,
+			})
+		}
+
+		switch {
+		case end == len(command) && node.Executable:
+			return next, nil
+		case end == len(command):
+			fail(&CommandSyntaxError{Pos: end, Msg: "incomplete command"})
+		case command[end] != ' ':
+			fail(&CommandSyntaxError{Pos: end, Msg: "expected whitespace"})
+		default:
+			result, err := parseCommand(node.next(), command, end+1, next)
+			if err == nil {
+				return result, nil
+			}
+			fail(err)
+		}
+	}
+	return nil, furthest
+}
+
+// readArgument returns the end of the argument of node starting at pos.
+func readArgument(node *CommandNode, command string, pos int) (int, error) {
+	word := command[pos:]
+	if i := strings.IndexByte(word, ' '); i >= 0 {
+		word = word[:i]
+	}
+	end := pos + len(word)
+
+	switch node.Parser {
+	case "brigadier:bool":
+		if word != "true" && word != "false" {
+			return 0, fmt.Errorf("expected true or false, got %q", word)
+		}
+		return end, nil
+	case "brigadier:integer", "brigadier:long":
+		v, err := strconv.ParseInt(word, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected integer, got %q", word)
+		}
+		return end, checkBounds(float64(v), node.Properties)
+	case "brigadier:float", "brigadier:double":
+		v, err := strconv.ParseFloat(word, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected number, got %q", word)
+		}
+		return end, checkBounds(v, node.Properties)
+	case "brigadier:string":
+		switch node.Properties {
+		case proto.StringGreedyPhrase:
+			return len(command), nil
+		case proto.StringQuotablePhrase:
+			if strings.HasPrefix(word, `"`) || strings.HasPrefix(word, "'") {
+				return readQuoted(command, pos)
+			}
+		}
+		if word == "" {
+			return 0, fmt.Errorf("expected string")
+		}
+		return end, nil
+	case "minecraft:message":
+		return len(command), nil
+	case "minecraft:block_pos", "minecraft:vec3":
+		return readCoordinates(command, pos, 3)
+	case "minecraft:column_pos", "minecraft:vec2", "minecraft:rotation":
+		return readCoordinates(command, pos, 2)
+	case "minecraft:angle":
+		return readCoordinates(command, pos, 1)
+	}
+	return readToken(command, pos)
+}
+
+func checkBounds(v float64, properties any) error {
+	var flags proto.Byte
+	var min, max float64
+	switch p := properties.(type) {
+	case proto.NumberProperties[proto.Int]:
+		flags, min, max = p.Flags, float64(p.Min), float64(p.Max)
+	case proto.NumberProperties[proto.Long]:
+		flags, min, max = p.Flags, float64(p.Min), float64(p.Max)
+	case proto.NumberProperties[proto.Float]:
+		flags, min, max = p.Flags, float64(p.Min), float64(p.Max)
+	case proto.NumberProperties[proto.Double]:
+		flags, min, max = p.Flags, float64(p.Min), float64(p.Max)
+	}
+
+	if flags&proto.NumberHasMin != 0 && v < min {
+		return fmt.Errorf("%v is below minimum %v", v, min)
+	}
+	if flags&proto.NumberHasMax != 0 && v > max {
+		return fmt.Errorf("%v is above maximum %v", v, max)
+	}
+	return nil
+}
+
+// readQuoted returns the end of the quoted string starting at pos.
+func readQuoted(command string, pos int) (int, error) {
+	quote := command[pos]
+	for i := pos + 1; i < len(command); i++ {
+		switch command[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed quoted string")
+}
+
+// readCoordinates returns the end of n space separated coordinates, each a
+// number optionally prefixed with '~' or '^'.
+func readCoordinates(command string, pos, n int) (int, error) {
+	end := pos
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			if end >= len(command) || command[end] != ' ' {
+				return 0, fmt.Errorf("expected %d coordinates", n)
+			}
+			end++
+		}
+
+		start := end
+		for end < len(command) && command[end] != ' ' {
+			end++
+		}
+		coordinate := command[start:end]
+		number := strings.TrimLeft(coordinate, "~^")
+		if coordinate == "" || len(coordinate)-len(number) > 1 {
+			return 0, fmt.Errorf("invalid coordinate %q", coordinate)
+		}
+		if _, err := strconv.ParseFloat(number, 64); number != "" && err != nil {
+			return 0, fmt.Errorf("invalid coordinate %q", coordinate)
+		}
+	}
+	return end, nil
+}
+
+// readToken returns the end of a token that ends with a space outside of
+// brackets and quotes, which covers selectors, NBT and block states.
+func readToken(command string, pos int) (int, error) {
+	depth := 0
+	var quote byte
+	i := pos
+	for ; i < len(command); i++ {
+		ch := command[i]
+		switch {
+		case quote != 0 && ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '[' || ch == '{' || ch == '(':
+			depth++
+		case ch == ']' || ch == '}' || ch == ')':
+			if depth--; depth < 0 {
+				return 0, fmt.Errorf("unexpected %q", ch)
+			}
+		case ch == ' ' && depth == 0:
+			return i, nil
+		}
+	}
+
+	switch {
+	case quote != 0:
+		return 0, fmt.Errorf("unclosed quoted string")
+	case depth > 0:
+		return 0, fmt.Errorf("unclosed bracket")
+	case i == pos:
+		return 0, fmt.Errorf("expected argument")
+	}
+	return i, nil
+}
+
+func (c *Client) handleCommands(pk proto.Packet) error {
+	var commands proto.CommandsResponse
+	if err := pk.Scan(&commands); err != nil {
+		return err
+	}
+
+	nodes := make([]*CommandNode, len(commands.Nodes))
+	for i, n := range commands.Nodes {
+		nodes[i] = &CommandNode{
+			Type:        byte(n.Flags & proto.NodeTypeMask),
+			Name:        string(n.Name),
+			Executable:  n.Flags&proto.NodeExecutable != 0,
+			Properties:  n.Properties,
+			Suggestions: string(n.Suggestions),
+		}
+		if nodes[i].Type == proto.NodeArgument {
+			nodes[i].Parser = n.Parser()
+		}
+	}
+
+	node := func(i proto.VarInt) (*CommandNode, error) {
+		if i < 0 || int(i) >= len(nodes) {
+			return nil, fmt.Errorf("command node index %d out of range", i)
+		}
+		return nodes[i], nil
+	}
+	for i, n := range commands.Nodes {
+		for _, child := range n.Children {
+			childNode, err := node(child)
+			if err != nil {
+				return err
+			}
+			nodes[i].Children = append(nodes[i].Children, childNode)
+		}
+		if n.Flags&proto.NodeHasRedirect != 0 {
+			redirect, err := node(n.Redirect)
+			if err != nil {
+				return err
+			}
+			nodes[i].Redirect = redirect
+		}
+	}
+
+	root, err := node(commands.Root)
+	if err != nil {
+		return err
+	}
+
+	c.Commands.mu.Lock()
+	defer c.Commands.mu.Unlock()
+	c.Commands.root = root
+	return nil
+}
+
+// Suggestion is a completion of the text from Suggestions.Start.
+type Suggestion struct {
+	Text    string
+	Tooltip proto.Chat
+}
+
+type Suggestions struct {
+	Start, Length int // Start and Length select the part of the text the suggestions replace
+	Matches       []Suggestion
+}
+
+// suggestionRequests matches Command Suggestions Responses to requests.
+type suggestionRequests struct {
+	mu      sync.Mutex
+	next    int32
+	pending map[int32]chan proto.CommandSuggestionsResponse
+}
+
+// Suggest asks the server for tab completions of text, e.g. "/gamemode cr".
+// The leading '/' is added when missing.
+func (c *Client) Suggest(ctx context.Context, text string) (Suggestions, error) {
+	if !strings.HasPrefix(text, "/") {
+		text = "/" + text
+	}
+
+	requests := &c.suggestions
+	response := make(chan proto.CommandSuggestionsResponse, 1)
+	requests.mu.Lock()
+	requests.next++
+	id := requests.next
+	if requests.pending == nil {
+		requests.pending = map[int32]chan proto.CommandSuggestionsResponse{}
+	}
+	requests.pending[id] = response
+	requests.mu.Unlock()
+
+	defer func() {
+		requests.mu.Lock()
+		delete(requests.pending, id)
+		requests.mu.Unlock()
+	}()
+
+	packet := proto.NewPacket(0x09)
+	if err := packet.Append(&RequestCommandSuggestions{ID: proto.VarInt(id), Text: proto.String(text)}); err != nil {
+		return Suggestions{}, err
+	}
+	if err := c.SendPacket(packet); err != nil {
+		return Suggestions{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return Suggestions{}, ctx.Err()
+	case r := <-response:
+		suggestions := Suggestions{Start: int(r.Start), Length: int(r.Length)}
+		for _, s := range r.Suggestions {
+			suggestions.Matches = append(suggestions.Matches, Suggestion{Text: string(s.Match), Tooltip: s.Tooltip.Value})
+		}
+		return suggestions, nil
+	}
+}
+
+func (c *Client) handleCommandSuggestions(pk proto.Packet) error {
+	var response proto.CommandSuggestionsResponse
+	if err := pk.Scan(&response); err != nil {
+		return err
+	}
+
+	c.suggestions.mu.Lock()
+	defer c.suggestions.mu.Unlock()
+	if ch, ok := c.suggestions.pending[int32(response.ID)]; ok {
+		ch <- response
+		delete(c.suggestions.pending, int32(response.ID))
+	}
+	return nil
+}
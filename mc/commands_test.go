@@ -0,0 +1,134 @@
+package mc
+
+import (
+	"errors"
+	"mc-bot/mc/proto"
+	"reflect"
+	"testing"
+)
+
+func testCommandGraph() *Commands {
+	literal := func(name string, executable bool, children ...*CommandNode) *CommandNode {
+		return &CommandNode{Type: proto.NodeLiteral, Name: name, Executable: executable, Children: children}
+	}
+	argument := func(name, parser string, properties any, executable bool, children ...*CommandNode) *CommandNode {
+		return &CommandNode{Type: proto.NodeArgument, Name: name, Parser: parser, Properties: properties, Executable: executable, Children: children}
+	}
+
+	root := &CommandNode{Type: proto.NodeRoot}
+	count := proto.NumberProperties[proto.Int]{Flags: proto.NumberHasMin | proto.NumberHasMax, Min: 1, Max: 64}
+	root.Children = []*CommandNode{
+		literal("gamemode", false,
+			literal("creative", true),
+			argument("mode", "brigadier:string", proto.StringSingleWord, true),
+		),
+		literal("tp", false, argument("pos", "minecraft:block_pos", nil, true)),
+		literal("give", false,
+			argument("targets", "minecraft:entity", nil, false,
+				argument("item", "minecraft:item_stack", nil, true,
+					argument("count", "brigadier:integer", count, true),
+				),
+			),
+		),
+		literal("say", false, argument("message", "minecraft:message", nil, true)),
+		literal("tell", false, argument("name", "brigadier:string", proto.StringQuotablePhrase, false,
+			argument("flag", "brigadier:bool", nil, true),
+		)),
+		literal("execute", false, &CommandNode{Type: proto.NodeLiteral, Name: "run", Redirect: root}),
+	}
+	return &Commands{root: root}
+}
+
+func TestCommandsParse(t *testing.T) {
+	tests := []struct {
+		Input string
+		Nodes []string
+		Args  []ParsedArgument
+	}{
+		{"gamemode creative", []string{"gamemode", "creative"}, nil},
+		{"gamemode survival", []string{"gamemode", "mode"}, []ParsedArgument{
+			{Name: "mode", Parser: "brigadier:string", Value: "survival", Start: 9},
+		}},
+		{"tp ~ ~1 ^-2.5", []string{"tp", "pos"}, []ParsedArgument{
+			{Name: "pos", Parser: "minecraft:block_pos", Value: "~ ~1 ^-2.5", Start: 3},
+		}},
+		{`give @a[name="a b"] stone{x:[1]} 64`, []string{"give", "targets", "item", "count"}, []ParsedArgument{
+			{Name: "targets", Parser: "minecraft:entity", Value: `@a[name="a b"]`, Start: 5},
+			{Name: "item", Parser: "minecraft:item_stack", Value: "stone{x:[1]}", Start: 20},
+			{Name: "count", Parser: "brigadier:integer", Value: "64", Start: 33},
+		}},
+		{"say hello  world", []string{"say", "message"}, []ParsedArgument{
+			{Name: "message", Parser: "minecraft:message", Value: "hello  world", Start: 4},
+		}},
+		{`tell "a \" b" true`, []string{"tell", "name", "flag"}, []ParsedArgument{
+			{Name: "name", Parser: "brigadier:string", Value: `"a \" b"`, Start: 5},
+			{Name: "flag", Parser: "brigadier:bool", Value: "true", Start: 14},
+		}},
+		{"execute run say hi", []string{"execute", "run", "say", "message"}, []ParsedArgument{
+			{Name: "message", Parser: "minecraft:message", Value: "hi", Start: 16},
+		}},
+	}
+
+	commands := testCommandGraph()
+	for _, tt := range tests {
+		t.Run(tt.Input, func(t *testing.T) {
+			parsed, err := commands.Parse(tt.Input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var nodes []string
+			for _, node := range parsed.Nodes {
+				nodes = append(nodes, node.Name)
+			}
+			if !reflect.DeepEqual(tt.Nodes, nodes) {
+				t.Errorf("Want nodes: %v, Got: %v", tt.Nodes, nodes)
+			}
+			if !reflect.DeepEqual(tt.Args, parsed.Arguments) {
+				t.Errorf("Want arguments: %#v, Got: %#v", tt.Args, parsed.Arguments)
+			}
+		})
+	}
+}
+
+func TestCommandsParseErrors(t *testing.T) {
+	tests := []struct {
+		Input string
+		Pos   int
+	}{
+		{"", 0},
+		{"fly", 0},
+		{"gamemode", 8},
+		{"gamemodes creative", 0},
+		{"tp 1 2", 3},
+		{"tp 1 2 x", 3},
+		{"give @a[ stone", 5},
+		{"give @a stone 65", 14},
+		{"give @a stone 0", 14},
+		{"give @a", 7},
+		{`tell "open true`, 5},
+		{"tell bob maybe", 9},
+		{"execute run fly", 12},
+	}
+
+	commands := testCommandGraph()
+	for _, tt := range tests {
+		t.Run(tt.Input, func(t *testing.T) {
+			_, err := commands.Parse(tt.Input)
+			var syntaxErr *CommandSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Want CommandSyntaxError, Got: %v", err)
+			}
+			if syntaxErr.Pos != tt.Pos {
+				t.Errorf("Want position: %d, Got: %d (%v)", tt.Pos, syntaxErr.Pos, err)
+			}
+		})
+	}
+}
+
+func TestCommandsParseNoGraph(t *testing.T) {
+	var commands Commands
+	if _, err := commands.Parse("say hi"); err == nil {
+		t.Error("Want error before the command graph is received")
+	}
+}
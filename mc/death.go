@@ -0,0 +1,198 @@
+package mc
+
+import (
+	"log"
+	"mc-bot/mc/proto"
+	"sync"
+	"time"
+)
+
+// maxDeaths is how many deaths DeathLog keeps.
+const maxDeaths = 100
+
+type DeathAction byte
+
+const (
+	DeathRespawn    DeathAction = iota // DeathRespawn respawns after DeathPolicy.Delay
+	DeathStay                          // DeathStay stays on the death screen until PerformRespawn is called
+	DeathDisconnect                    // DeathDisconnect closes the connection
+)
+
+// DeathPolicy is what the client does when the player dies. The zero value
+// respawns right away.
+type DeathPolicy struct {
+	Action DeathAction
+	Delay  time.Duration
+}
+
+// Death describes a death of the player.
+type Death struct {
+	Time      time.Time
+	Dimension string
+	X, Y, Z   float64
+	Message   proto.Chat // Message is the death message shown on the death screen
+
+	// Killer is the name of the killer from the death message, empty when
+	// the player was not killed by an entity.
+	Killer string
+	// KillerID is the entity that caused the last damage, -1 if none.
+	KillerID int32
+	// DamageType is the type of the last damage, e.g. "minecraft:mob_attack",
+	// empty if not known.
+	DamageType string
+}
+
+// DeathEvent is emitted when the player dies, before DeathPolicy is applied.
+type DeathEvent struct {
+	Death Death
+}
+
+// DeathLog records the deaths of the player with their locations, e.g. to
+// recover dropped items. It is safe for concurrent use.
+type DeathLog struct {
+	mu     sync.RWMutex
+	deaths []Death
+}
+
+// All returns the recorded deaths, oldest first.
+func (l *DeathLog) All() []Death {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Death(nil), l.deaths...)
+}
+
+// Last returns the most recent death.
+func (l *DeathLog) Last() (Death, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if len(l.deaths) == 0 {
+		return Death{}, false
+	}
+	return l.deaths[len(l.deaths)-1], true
+}
+
+func (l *DeathLog) add(death Death) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deaths = append(l.deaths, death)
+	if len(l.deaths) > maxDeaths {
+		l.deaths = l.deaths[len(l.deaths)-maxDeaths:]
+	}
+}
+
+// deathState makes sure the policy is applied once per death and remembers
+// the last damage taken, which names the killer.
+type deathState struct {
+	mu         sync.Mutex
+	dead       bool
+	killerID   int32
+	damageType int32
+}
+
+// die marks the player dead and reports whether it was alive.
+func (d *deathState) die() (killerID, damageType int32, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.dead {
+		return 0, 0, false
+	}
+	d.dead = true
+	return d.killerID, d.damageType, true
+}
+
+func (d *deathState) damage(killerID, damageType int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.killerID, d.damageType = killerID, damageType
+}
+
+// reset is called when the player (re)spawns.
+func (d *deathState) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dead, d.killerID, d.damageType = false, -1, -1
+}
+
+// Dead reports whether the player is on the death screen.
+func (c *Client) Dead() bool {
+	c.death.mu.Lock()
+	defer c.death.mu.Unlock()
+	return c.death.dead
+}
+
+// deathKiller returns the killer from a death message. Death messages are
+// translated with the victim as the first argument and the killer, if any,
+// as the second one.
+func deathKiller(message proto.Chat) string {
+	component, err := message.Component()
+	if err != nil || len(component.With) < 2 {
+		return ""
+	}
+	return component.With[1].String()
+}
+
+func (c *Client) handleDamageEvent(pk proto.Packet) error {
+	var damage proto.DamageEventResponse
+	if err := pk.Scan(&damage); err != nil {
+		return err
+	}
+
+	if int32(damage.EntityID) == c.Player.EntityID() {
+		c.death.damage(int32(damage.SourceCauseID)-1, int32(damage.SourceTypeID))
+	}
+	return nil
+}
+
+func (c *Client) handleCombatDeathPacket(pk proto.Packet) error {
+	var death proto.CombatDeathResponse
+	if err := pk.Scan(&death); err != nil {
+		return err
+	}
+
+	if int32(death.PlayerID) != c.Player.EntityID() {
+		return nil
+	}
+	killerID, damageType, ok := c.death.die()
+	if !ok {
+		return nil
+	}
+
+	state := c.Player.State()
+	record := Death{
+		Time:      time.Now(),
+		Dimension: state.Dimension,
+		X:         state.X,
+		Y:         state.Y,
+		Z:         state.Z,
+		Message:   death.Message,
+		Killer:    deathKiller(death.Message),
+		KillerID:  killerID,
+	}
+	if damage, ok := c.Registries.DamageType(damageType); ok {
+		record.DamageType = damage.Name
+	}
+	c.Deaths.add(record)
+
+	log.Printf("[INFO] Player '%s' died at %.0f %.0f %.0f in %s: %s\n",
+		c.Player.Name(), record.X, record.Y, record.Z, record.Dimension, death.Message.PlainText())
+	c.emit(DeathEvent{Death: record})
+
+	policy := c.OnDeath
+	switch policy.Action {
+	case DeathRespawn:
+		if policy.Delay <= 0 {
+			return c.PerformRespawn()
+		}
+		time.AfterFunc(policy.Delay, func() {
+			if err := c.PerformRespawn(); err != nil {
+				log.Printf("[WARN] cannot respawn: %v", err)
+			}
+		})
+	case DeathDisconnect:
+		return c.Close()
+	}
+	return nil
+}
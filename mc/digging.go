@@ -0,0 +1,346 @@
+package mc
+
+import (
+	"context"
+	"math"
+	"mc-bot/mc/proto"
+	"sync"
+	"time"
+)
+
+const (
+	// reachSurvival and reachCreative are the block interaction ranges
+	// measured from the eyes.
+	reachSurvival = 4.5
+	reachCreative = 5.0
+	// ackTimeout limits waiting for the server to acknowledge an interaction.
+	ackTimeout = 2 * time.Second
+)
+
+// BlockChangeAcknowledgedEvent is emitted when the server has processed all
+// block interactions up to Sequence.
+type BlockChangeAcknowledgedEvent struct {
+	Sequence int32
+}
+
+// sequenceAcks remembers the highest acknowledged interaction sequence so
+// that waiting for it cannot miss an acknowledgement sent before the wait.
+type sequenceAcks struct {
+	mu      sync.Mutex
+	last    int32
+	changed chan struct{}
+}
+
+func newSequenceAcks() *sequenceAcks {
+	return &sequenceAcks{changed: make(chan struct{})}
+}
+
+func (a *sequenceAcks) acknowledge(sequence int32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if sequence > a.last {
+		a.last = sequence
+	}
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+func (a *sequenceAcks) wait(ctx context.Context, sequence int32) error {
+	for {
+		a.mu.Lock()
+		last, changed := a.last, a.changed
+		a.mu.Unlock()
+
+		if last >= sequence {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// waitAcknowledged waits until the server acknowledges sequence, giving up
+// after ackTimeout.
+func (c *Client) waitAcknowledged(ctx context.Context, sequence proto.VarInt) error {
+	ctx, cancel := context.WithTimeout(ctx, ackTimeout)
+	defer cancel()
+	return c.acks.wait(ctx, int32(sequence))
+}
+
+func (c *Client) handleAcknowledgeBlockChange(pk proto.Packet) error {
+	var ack proto.AcknowledgeBlockChangeResponse
+	if err := pk.Scan(&ack); err != nil {
+		return err
+	}
+
+	c.acks.acknowledge(int32(ack.Sequence))
+	c.emit(BlockChangeAcknowledgedEvent{Sequence: int32(ack.Sequence)})
+	return nil
+}
+
+// Dig breaks the block at pos. It looks at the block, holds the attack
+// button for as long as the block takes to break with the held item and
+// waits for the server to confirm the change. Canceling ctx aborts digging.
+// RunPhysics should be running so that the on-ground state stays current.
+// Client.Blocks must be set.
+func (c *Client) Dig(ctx context.Context, pos proto.Position) error {
+	if c.Blocks == nil {
+		return ErrNoBlockRegistry
+	}
+
+	state := c.World.BlockState(pos)
+	block := c.Blocks.Block(state)
+	if block.Hardness < 0 {
+		return ErrUnbreakable
+	}
+	if !c.inReach(pos) {
+		return ErrOutOfReach
+	}
+
+	face := c.facingFace(pos)
+	c.LookAt(float64(pos.X)+0.5, float64(pos.Y)+0.5, float64(pos.Z)+0.5)
+	if err := c.sendLook(); err != nil {
+		return err
+	}
+
+	sequence, err := c.SendPlayerAction(ActionStartedDigging, pos, face)
+	if err != nil {
+		return err
+	}
+	if err := c.SwingArm(HandMain); err != nil {
+		return err
+	}
+
+	// in creative mode and for instantly mined blocks start is enough
+	if c.Player.GameMode() != GameModeCreative && c.digProgress(block) < 1 {
+		ticker := time.NewTicker(TickDuration)
+		defer ticker.Stop()
+
+		// like vanilla, progress is made on each tick after the start, so
+		// the block breaks after ceil(1/progress) ticks
+		for progress := 0.0; progress < 1; progress += c.digProgress(block) {
+			select {
+			case <-ctx.Done():
+				_, _ = c.SendPlayerAction(ActionCancelledDigging, pos, face)
+				return ctx.Err()
+			case <-ticker.C:
+			}
+
+			if err := c.SwingArm(HandMain); err != nil {
+				return err
+			}
+		}
+
+		if sequence, err = c.SendPlayerAction(ActionFinishedDigging, pos, face); err != nil {
+			return err
+		}
+	}
+
+	if err := c.waitAcknowledged(ctx, sequence); err != nil {
+		return err
+	}
+	if c.World.BlockState(pos) == state {
+		return ErrDigRejected
+	}
+	return nil
+}
+
+// DigTime returns how long breaking the block at pos takes with the held
+// item in the current player state. It returns -1 for unbreakable blocks.
+// Client.Blocks must be set.
+func (c *Client) DigTime(pos proto.Position) (time.Duration, error) {
+	if c.Blocks == nil {
+		return 0, ErrNoBlockRegistry
+	}
+
+	block := c.block(pos)
+	if block.Hardness < 0 {
+		return -1, nil
+	}
+
+	progress := c.digProgress(block)
+	if c.Player.GameMode() == GameModeCreative || progress >= 1 {
+		return 0, nil
+	}
+	return time.Duration(math.Ceil(1/progress)) * TickDuration, nil
+}
+
+// digProgress returns the part of the block broken in one tick, following
+// the vanilla formula: tool speed with efficiency, haste and mining fatigue,
+// slowed down under water and in the air, divided by hardness.
+func (c *Client) digProgress(block Block) float64 {
+	if block.Hardness < 0 {
+		return 0
+	}
+	if block.Hardness == 0 {
+		return 1
+	}
+
+	held := c.HeldItem()
+	item := c.items().Item(int32(held.ItemID))
+	if held.Empty() {
+		item = Item{}
+	}
+
+	speed := 1.0
+	if item.Tool != ToolNone && item.Tool == block.Tool {
+		speed = float64(item.ToolSpeed)
+		if efficiency := Enchantment(held, "minecraft:efficiency"); efficiency > 0 && speed > 1 {
+			speed += float64(efficiency*efficiency + 1)
+		}
+	}
+
+	entity, _ := c.PlayerEntity()
+	haste, hasHaste := entity.Effect(EffectHaste)
+	if conduit, ok := entity.Effect(EffectConduitPower); ok && (!hasHaste || conduit.Amplifier > haste.Amplifier) {
+		haste, hasHaste = conduit, true
+	}
+	if hasHaste {
+		speed *= 1 + float64(haste.Amplifier+1)*0.2
+	}
+
+	if fatigue, ok := entity.Effect(EffectMiningFatigue); ok {
+		switch fatigue.Amplifier {
+		case 0:
+			speed *= 0.3
+		case 1:
+			speed *= 0.09
+		case 2:
+			speed *= 0.0027
+		default:
+			speed *= 8.1e-4
+		}
+	}
+
+	state := c.Player.State()
+	eyes := proto.Position{
+		X: int32(math.Floor(state.X)),
+		Y: int32(math.Floor(state.eyeY())),
+		Z: int32(math.Floor(state.Z)),
+	}
+	if c.block(eyes).Fluid == FluidWater && Enchantment(c.Inventory.Slot(SlotHelmet), "minecraft:aqua_affinity") == 0 {
+		speed /= 5
+	}
+	if !state.OnGround {
+		speed /= 5
+	}
+
+	divisor := 30.0
+	if block.RequiresTool && (item.Tool != block.Tool || item.ToolTier < block.HarvestTier) {
+		divisor = 100
+	}
+	return speed / float64(block.Hardness) / divisor
+}
+
+// PlaceBlock places the held block against the face of the block at pos,
+// so the new block ends up next to pos in the direction of face. It waits
+// for the server to confirm the placement.
+func (c *Client) PlaceBlock(ctx context.Context, pos proto.Position, face proto.Direction) error {
+	if !c.inReach(pos) {
+		return ErrOutOfReach
+	}
+
+	cx, cy, cz := faceCenter(face)
+	c.LookAt(float64(pos.X)+float64(cx), float64(pos.Y)+float64(cy), float64(pos.Z)+float64(cz))
+	if err := c.sendLook(); err != nil {
+		return err
+	}
+
+	target := offset(pos, face)
+	before := c.World.BlockState(target)
+
+	sequence, err := c.useItemOn(HandMain, pos, face, cx, cy, cz)
+	if err != nil {
+		return err
+	}
+	if err := c.SwingArm(HandMain); err != nil {
+		return err
+	}
+
+	if err := c.waitAcknowledged(ctx, sequence); err != nil {
+		return err
+	}
+	if c.World.BlockState(target) == before {
+		return ErrPlaceRejected
+	}
+	return nil
+}
+
+// inReach reports whether the center of the block at pos is close enough
+// to the eyes to interact with it.
+func (c *Client) inReach(pos proto.Position) bool {
+	state := c.Player.State()
+	reach := reachSurvival
+	if state.GameMode == GameModeCreative {
+		reach = reachCreative
+	}
+
+	dx := float64(pos.X) + 0.5 - state.X
+	dy := float64(pos.Y) + 0.5 - state.eyeY()
+	dz := float64(pos.Z) + 0.5 - state.Z
+	// the server measures to the block center with some leeway
+	return dx*dx+dy*dy+dz*dz <= (reach+1)*(reach+1)
+}
+
+// facingFace returns the face of the block at pos that is turned to the
+// player eyes.
+func (c *Client) facingFace(pos proto.Position) proto.Direction {
+	state := c.Player.State()
+	dx := state.X - (float64(pos.X) + 0.5)
+	dy := state.eyeY() - (float64(pos.Y) + 0.5)
+	dz := state.Z - (float64(pos.Z) + 0.5)
+
+	switch ax, ay, az := math.Abs(dx), math.Abs(dy), math.Abs(dz); {
+	case ay >= ax && ay >= az && dy > 0:
+		return proto.DirectionUp
+	case ay >= ax && ay >= az:
+		return proto.DirectionDown
+	case ax >= az && dx > 0:
+		return proto.DirectionEast
+	case ax >= az:
+		return proto.DirectionWest
+	case dz > 0:
+		return proto.DirectionSouth
+	}
+	return proto.DirectionNorth
+}
+
+// faceCenter returns the cursor position in the middle of face.
+func faceCenter(face proto.Direction) (x, y, z float32) {
+	switch face {
+	case proto.DirectionDown:
+		return 0.5, 0, 0.5
+	case proto.DirectionUp:
+		return 0.5, 1, 0.5
+	case proto.DirectionNorth:
+		return 0.5, 0.5, 0
+	case proto.DirectionSouth:
+		return 0.5, 0.5, 1
+	case proto.DirectionWest:
+		return 0, 0.5, 0.5
+	}
+	return 1, 0.5, 0.5
+}
+
+// offset returns the neighbour of pos in the direction of face.
+func offset(pos proto.Position, face proto.Direction) proto.Position {
+	switch face {
+	case proto.DirectionDown:
+		pos.Y--
+	case proto.DirectionUp:
+		pos.Y++
+	case proto.DirectionNorth:
+		pos.Z--
+	case proto.DirectionSouth:
+		pos.Z++
+	case proto.DirectionWest:
+		pos.X--
+	case proto.DirectionEast:
+		pos.X++
+	}
+	return pos
+}
@@ -0,0 +1,392 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+// Entity metadata indexes shared by all entities, see
+// https://wiki.vg/index.php?title=Entity_metadata&oldid=18375#Entity
+const (
+	MetaIndexFlags             = 0
+	MetaIndexAirTicks          = 1
+	MetaIndexCustomName        = 2
+	MetaIndexCustomNameVisible = 3
+	MetaIndexSilent            = 4
+	MetaIndexNoGravity         = 5
+	MetaIndexPose              = 6
+	MetaIndexTicksFrozen       = 7
+
+	// MetaIndexItem is the item of item entities, item frames and thrown items.
+	MetaIndexItem = 8
+	// MetaIndexHealth is the health of living entities.
+	MetaIndexHealth = 9
+)
+
+// Bits of the entity flags stored under MetaIndexFlags.
+const (
+	EntityFlagOnFire     = 0x01
+	EntityFlagCrouching  = 0x02
+	EntityFlagSprinting  = 0x08
+	EntityFlagSwimming   = 0x10
+	EntityFlagInvisible  = 0x20
+	EntityFlagGlowing    = 0x40
+	EntityFlagFallFlying = 0x80
+)
+
+type Entity struct {
+	ID       int32
+	UUID     proto.Uuid
+	Type     int32 // Type is an ID from the minecraft:entity_type registry or one of EntityType* constants
+	X, Y, Z  float64
+	Yaw      float32
+	Pitch    float32
+	HeadYaw  float32
+	OnGround bool
+	Metadata map[uint8]proto.MetadataEntry
+
+	VelX, VelY, VelZ float64 // VelX, VelY and VelZ are the last velocity sent by the server in blocks per tick
+
+	Attributes map[string]Attribute
+	Effects    map[int32]Effect
+}
+
+// Entity types of entities spawned by dedicated packets instead of Spawn
+// Entity, which carry no registry ID.
+const (
+	EntityTypePlayer        = -1
+	EntityTypeExperienceOrb = -2
+)
+
+func (e *Entity) metaValue(index uint8) any {
+	entry, ok := e.Metadata[index]
+	if !ok {
+		return nil
+	}
+	return entry.Value
+}
+
+// Flags returns the entity flags bitmask (see EntityFlagOnFire and others).
+func (e *Entity) Flags() byte {
+	v, _ := e.metaValue(MetaIndexFlags).(proto.Byte)
+	return byte(v)
+}
+
+func (e *Entity) Sneaking() bool {
+	return e.Flags()&EntityFlagCrouching != 0
+}
+
+// CustomName returns the raw JSON chat component of the entity name tag.
+func (e *Entity) CustomName() (proto.Chat, bool) {
+	v, _ := e.metaValue(MetaIndexCustomName).(*proto.Chat)
+	if v == nil {
+		return "", false
+	}
+	return *v, true
+}
+
+func (e *Entity) Pose() proto.Pose {
+	v, _ := e.metaValue(MetaIndexPose).(proto.Pose)
+	return v
+}
+
+// Item returns the item carried by item entities (drops), item frames and
+// other entities storing a Slot under MetaIndexItem.
+func (e *Entity) Item() (proto.Slot, bool) {
+	v, ok := e.metaValue(MetaIndexItem).(proto.Slot)
+	return v, ok
+}
+
+// Health returns the health of living entities.
+func (e *Entity) Health() (float32, bool) {
+	v, ok := e.metaValue(MetaIndexHealth).(proto.Float)
+	return float32(v), ok
+}
+
+func (e *Entity) clone() Entity {
+	out := *e
+	out.Metadata = make(map[uint8]proto.MetadataEntry, len(e.Metadata))
+	for k, v := range e.Metadata {
+		out.Metadata[k] = v
+	}
+	out.Attributes = make(map[string]Attribute, len(e.Attributes))
+	for k, v := range e.Attributes {
+		out.Attributes[k] = v
+	}
+	out.Effects = make(map[int32]Effect, len(e.Effects))
+	for k, v := range e.Effects {
+		out.Effects[k] = v
+	}
+	return out
+}
+
+// Entities tracks entities spawned around the player. It is safe for
+// concurrent use.
+type Entities struct {
+	mu       sync.RWMutex
+	entities map[int32]*Entity
+}
+
+func newEntities() *Entities {
+	return &Entities{entities: make(map[int32]*Entity)}
+}
+
+// Get returns a copy of the entity with given id.
+func (e *Entities) Get(id int32) (Entity, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	entity, ok := e.entities[id]
+	if !ok {
+		return Entity{}, false
+	}
+	return entity.clone(), true
+}
+
+// ByUUID returns a copy of the entity with given UUID.
+func (e *Entities) ByUUID(uuid proto.Uuid) (Entity, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, entity := range e.entities {
+		if entity.UUID == uuid {
+			return entity.clone(), true
+		}
+	}
+	return Entity{}, false
+}
+
+// All returns copies of all tracked entities.
+func (e *Entities) All() []Entity {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := make([]Entity, 0, len(e.entities))
+	for _, entity := range e.entities {
+		out = append(out, entity.clone())
+	}
+	return out
+}
+
+func (e *Entities) add(entity *Entity) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if entity.Metadata == nil {
+		entity.Metadata = make(map[uint8]proto.MetadataEntry)
+	}
+	if entity.Attributes == nil {
+		entity.Attributes = make(map[string]Attribute)
+	}
+	if entity.Effects == nil {
+		entity.Effects = make(map[int32]Effect)
+	}
+	e.entities[entity.ID] = entity
+}
+
+func (e *Entities) remove(ids ...int32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, id := range ids {
+		delete(e.entities, id)
+	}
+}
+
+// update calls fn with the entity while holding the lock. It does nothing
+// if the entity is not tracked.
+func (e *Entities) update(id int32, fn func(entity *Entity)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if entity, ok := e.entities[id]; ok {
+		fn(entity)
+	}
+}
+
+func (e *Entities) clear() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.entities = make(map[int32]*Entity)
+}
+
+// deltaPosition converts a relative move sent as a Short to blocks.
+func deltaPosition(d proto.Short) float64 {
+	return float64(d) / 4096
+}
+
+// velocity converts a velocity sent as a Short to blocks per tick.
+func velocity(v proto.Short) float64 {
+	return float64(v) / 8000
+}
+
+func (c *Client) handleSpawnEntity(pk proto.Packet) error {
+	var spawn proto.SpawnEntityResponse
+	if err := pk.Scan(&spawn); err != nil {
+		return err
+	}
+
+	c.Entities.add(&Entity{
+		ID:      int32(spawn.EntityID),
+		UUID:    spawn.EntityUUID,
+		Type:    int32(spawn.Type),
+		X:       float64(spawn.X),
+		Y:       float64(spawn.Y),
+		Z:       float64(spawn.Z),
+		Yaw:     spawn.Yaw.Degrees(),
+		Pitch:   spawn.Pitch.Degrees(),
+		HeadYaw: spawn.HeadYaw.Degrees(),
+		VelX:    velocity(spawn.VelocityX),
+		VelY:    velocity(spawn.VelocityY),
+		VelZ:    velocity(spawn.VelocityZ),
+	})
+	return nil
+}
+
+func (c *Client) handleSpawnExperienceOrb(pk proto.Packet) error {
+	var spawn proto.SpawnExperienceOrbResponse
+	if err := pk.Scan(&spawn); err != nil {
+		return err
+	}
+
+	c.Entities.add(&Entity{
+		ID:   int32(spawn.EntityID),
+		Type: EntityTypeExperienceOrb,
+		X:    float64(spawn.X),
+		Y:    float64(spawn.Y),
+		Z:    float64(spawn.Z),
+	})
+	return nil
+}
+
+func (c *Client) handleSpawnPlayer(pk proto.Packet) error {
+	var spawn proto.SpawnPlayerResponse
+	if err := pk.Scan(&spawn); err != nil {
+		return err
+	}
+
+	c.Entities.add(&Entity{
+		ID:    int32(spawn.EntityID),
+		UUID:  spawn.PlayerUUID,
+		Type:  EntityTypePlayer,
+		X:     float64(spawn.X),
+		Y:     float64(spawn.Y),
+		Z:     float64(spawn.Z),
+		Yaw:   spawn.Yaw.Degrees(),
+		Pitch: spawn.Pitch.Degrees(),
+	})
+	return nil
+}
+
+func (c *Client) handleRemoveEntities(pk proto.Packet) error {
+	var removed proto.RemoveEntitiesResponse
+	if err := pk.Scan(&removed); err != nil {
+		return err
+	}
+
+	ids := make([]int32, len(removed.EntityIDs))
+	for i, id := range removed.EntityIDs {
+		ids[i] = int32(id)
+	}
+	c.Entities.remove(ids...)
+	return nil
+}
+
+func (c *Client) handleUpdateEntityPosition(pk proto.Packet) error {
+	var move proto.UpdateEntityPositionResponse
+	if err := pk.Scan(&move); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(move.EntityID), func(entity *Entity) {
+		entity.X += deltaPosition(move.DeltaX)
+		entity.Y += deltaPosition(move.DeltaY)
+		entity.Z += deltaPosition(move.DeltaZ)
+		entity.OnGround = bool(move.OnGround)
+	})
+	return nil
+}
+
+func (c *Client) handleUpdateEntityPositionAndRotation(pk proto.Packet) error {
+	var move proto.UpdateEntityPositionAndRotationResponse
+	if err := pk.Scan(&move); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(move.EntityID), func(entity *Entity) {
+		entity.X += deltaPosition(move.DeltaX)
+		entity.Y += deltaPosition(move.DeltaY)
+		entity.Z += deltaPosition(move.DeltaZ)
+		entity.Yaw, entity.Pitch = move.Yaw.Degrees(), move.Pitch.Degrees()
+		entity.OnGround = bool(move.OnGround)
+	})
+	return nil
+}
+
+func (c *Client) handleUpdateEntityRotation(pk proto.Packet) error {
+	var move proto.UpdateEntityRotationResponse
+	if err := pk.Scan(&move); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(move.EntityID), func(entity *Entity) {
+		entity.Yaw, entity.Pitch = move.Yaw.Degrees(), move.Pitch.Degrees()
+		entity.OnGround = bool(move.OnGround)
+	})
+	return nil
+}
+
+func (c *Client) handleTeleportEntity(pk proto.Packet) error {
+	var tp proto.TeleportEntityResponse
+	if err := pk.Scan(&tp); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(tp.EntityID), func(entity *Entity) {
+		entity.X, entity.Y, entity.Z = float64(tp.X), float64(tp.Y), float64(tp.Z)
+		entity.Yaw, entity.Pitch = tp.Yaw.Degrees(), tp.Pitch.Degrees()
+		entity.OnGround = bool(tp.OnGround)
+	})
+	return nil
+}
+
+func (c *Client) handleSetHeadRotation(pk proto.Packet) error {
+	var rot proto.SetHeadRotationResponse
+	if err := pk.Scan(&rot); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(rot.EntityID), func(entity *Entity) {
+		entity.HeadYaw = rot.HeadYaw.Degrees()
+	})
+	return nil
+}
+
+func (c *Client) handleSetEntityVelocity(pk proto.Packet) error {
+	var vel proto.SetEntityVelocityResponse
+	if err := pk.Scan(&vel); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(vel.EntityID), func(entity *Entity) {
+		entity.VelX = velocity(vel.VelocityX)
+		entity.VelY = velocity(vel.VelocityY)
+		entity.VelZ = velocity(vel.VelocityZ)
+	})
+	return nil
+}
+
+func (c *Client) handleSetEntityMetadata(pk proto.Packet) error {
+	var meta proto.SetEntityMetadataResponse
+	if err := pk.Scan(&meta); err != nil {
+		return err
+	}
+
+	c.Entities.update(int32(meta.EntityID), func(entity *Entity) {
+		for _, entry := range meta.Metadata {
+			entity.Metadata[uint8(entry.Index)] = entry
+		}
+	})
+	return nil
+}
@@ -0,0 +1,43 @@
+package mc
+
+// EntityType describes an entity type.
+type EntityType struct {
+	Name    string  // Name is e.g. "minecraft:zombie", empty when not known
+	Width   float64 // Width and Height are the size of the hitbox
+	Height  float64
+	Hostile bool // Hostile is set for monsters attacking players on sight
+}
+
+// EntityTypeRegistry describes entity type IDs. Like ItemRegistry it has to
+// be filled from data generated for the server version.
+type EntityTypeRegistry interface {
+	EntityType(id int32) EntityType
+}
+
+// DefaultEntityTypes is used when Client.EntityTypes is nil. It knows only
+// players and treats other entities as passive and player-sized.
+var DefaultEntityTypes EntityTypeRegistry = defaultEntityTypes{}
+
+type defaultEntityTypes struct{}
+
+func (defaultEntityTypes) EntityType(id int32) EntityType {
+	if id == EntityTypePlayer {
+		return EntityType{Name: "minecraft:player", Width: playerWidth, Height: playerHeight}
+	}
+	return EntityType{Width: playerWidth, Height: playerHeight}
+}
+
+func (c *Client) entityTypes() EntityTypeRegistry {
+	if c.EntityTypes == nil {
+		return DefaultEntityTypes
+	}
+	return c.EntityTypes
+}
+
+// Box returns the hitbox of the entity.
+func (e *Entity) Box(kind EntityType) AABB {
+	return AABB{
+		MinX: e.X - kind.Width/2, MinY: e.Y, MinZ: e.Z - kind.Width/2,
+		MaxX: e.X + kind.Width/2, MaxY: e.Y + kind.Height, MaxZ: e.Z + kind.Width/2,
+	}
+}
@@ -1 +1,43 @@
 package mc
+
+import "errors"
+
+var (
+	ErrNotConnected = errors.New("client is not connected")
+
+	ErrNoBlockRegistry      = errors.New("Client.Blocks is not set")
+	ErrNoEntityTypeRegistry = errors.New("Client.EntityTypes is not set")
+	ErrNoItemRegistry       = errors.New("Client.Items is not set")
+
+	ErrNoPath = errors.New("no path to target")
+	ErrStuck  = errors.New("player got stuck following path")
+
+	ErrUnbreakable   = errors.New("block cannot be broken")
+	ErrOutOfReach    = errors.New("block is out of reach")
+	ErrDigRejected   = errors.New("server rejected digging the block")
+	ErrPlaceRejected = errors.New("server rejected placing the block")
+
+	ErrWindowClosed   = errors.New("window is not open")
+	ErrContainerOpen  = errors.New("a container is open")
+	ErrCursorNotEmpty = errors.New("cursor holds an item")
+	ErrSlotEmpty      = errors.New("slot is empty")
+	ErrSlotOccupied   = errors.New("slot holds another item")
+	ErrNotEquipment   = errors.New("item cannot be equipped")
+	ErrInventoryFull  = errors.New("inventory is full")
+
+	ErrUnknownEntity = errors.New("unknown entity")
+
+	ErrNoMerchant       = errors.New("no merchant window is open")
+	ErrUnknownTrade     = errors.New("unknown trade offer")
+	ErrTradeUnavailable = errors.New("trade is sold out")
+
+	ErrSignNotEditable = errors.New("server did not open the sign editor")
+	ErrNotWritableBook = errors.New("item is not a book and quill")
+
+	ErrNoFood         = errors.New("no food in inventory")
+	ErrEatInterrupted = errors.New("eating was interrupted")
+
+	ErrUnknownRecipe      = errors.New("unknown recipe")
+	ErrNotCraftable       = errors.New("recipe does not fit the crafting grid")
+	ErrMissingIngredients = errors.New("missing ingredients")
+)
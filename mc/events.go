@@ -0,0 +1,107 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+// Event is emitted to subscribers when the client state changes. Concrete
+// events are the *Event types of this package.
+type Event any
+
+// BlockChangedEvent is emitted when the server changes a block in a loaded
+// chunk.
+type BlockChangedEvent struct {
+	Position proto.Position
+	Old      int32
+	New      int32
+}
+
+// ContainerOpenedEvent is emitted when the server opens a container window.
+// Its slots arrive right after with an InventoryChangedEvent.
+type ContainerOpenedEvent struct {
+	Window Window
+}
+
+// ContainerClosedEvent is emitted when a container window is closed by the
+// server or by CloseContainer.
+type ContainerClosedEvent struct {
+	WindowID int32
+}
+
+// InventoryChangedEvent is emitted when slots of a window change. Slot is -1
+// when more than one slot changed. WindowID is -1 when only the item held by
+// the cursor changed.
+type InventoryChangedEvent struct {
+	WindowID int32
+	Slot     int16
+}
+
+// DimensionChangedEvent is emitted when the player joins the world and
+// after every respawn, which is also how the server changes dimensions.
+type DimensionChangedEvent struct {
+	Dimension string // Dimension is the world name, e.g. "minecraft:the_nether"
+	Type      DimensionType
+}
+
+// PlayerJoinedEvent is emitted when a player is added to the player list,
+// including the players already online when the client joins.
+type PlayerJoinedEvent struct {
+	Player PlayerListEntry
+}
+
+// PlayerLeftEvent is emitted when a player is removed from the player list.
+type PlayerLeftEvent struct {
+	Player PlayerListEntry
+}
+
+// PlayerUpdatedEvent is emitted when the game mode, latency, display name or
+// other fields of a listed player change. Actions are the PlayerInfo*
+// bits of the fields sent.
+type PlayerUpdatedEvent struct {
+	Player  PlayerListEntry
+	Actions byte
+}
+
+type events struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(Event)
+}
+
+func newEvents() *events {
+	return &events{handlers: make(map[int]func(Event))}
+}
+
+// Subscribe registers fn to be called with every event. Handlers are called
+// from the goroutine running HandleResponses, so they must not block;
+// long-running work should be moved to another goroutine. The returned
+// function removes the subscription.
+func (c *Client) Subscribe(fn func(Event)) (unsubscribe func()) {
+	e := c.events
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	id := e.nextID
+	e.nextID++
+	e.handlers[id] = fn
+
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.handlers, id)
+	}
+}
+
+func (c *Client) emit(event Event) {
+	c.events.mu.RLock()
+	handlers := make([]func(Event), 0, len(c.events.handlers))
+	for _, fn := range c.events.handlers {
+		handlers = append(handlers, fn)
+	}
+	c.events.mu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(event)
+	}
+}
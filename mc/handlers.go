@@ -11,6 +11,9 @@ type ReceiveGameEvent struct {
 	Value   proto.Float
 }
 
+// GameEventChangeGameMode https://wiki.vg/index.php?title=Protocol&oldid=18375#Game_Event
+const GameEventChangeGameMode = 3
+
 // HandleCompressionPacket https://wiki.vg/Protocol#Set_Compression
 func (c *Client) HandleCompressionPacket(pk proto.Packet) error {
 	threshold := proto.VarInt(-1)
@@ -23,7 +26,122 @@ func (c *Client) HandleCompressionPacket(pk proto.Packet) error {
 }
 
 func (c *Client) HandleLoginSuccessPacket(pk proto.Packet) error {
-	log.Println("TODO: login success packet")
+	var success proto.LoginSuccessResponse
+	if err := pk.Scan(&success); err != nil {
+		return err
+	}
+
+	// the server may assign another UUID, e.g. the one of the Mojang account
+	c.Player.setProfile(string(success.Username), success.UUID.String())
+	return nil
+}
+
+func (c *Client) handleLoginPlay(pk proto.Packet) error {
+	var login proto.LoginPlayResponse
+	if err := pk.Scan(&login); err != nil {
+		return err
+	}
+
+	c.Registries.load(login.RegistryCodec.Compound())
+	c.Player.update(func(state *PlayerState) {
+		state.EntityID = int32(login.EntityID)
+		state.GameMode = byte(login.GameMode)
+		state.Hardcore = bool(login.IsHardcore)
+	})
+	c.Entities.clear()
+	c.Entities.add(&Entity{ID: int32(login.EntityID), Type: EntityTypePlayer})
+	c.death.reset()
+	c.setDimension(string(login.DimensionName), string(login.DimensionType))
+	return c.startChatSession()
+}
+
+// Bits of SyncPlayerPositionResponse.Flags marking fields relative to the
+// current position instead of absolute.
+const (
+	TeleportRelativeX     = 0x01
+	TeleportRelativeY     = 0x02
+	TeleportRelativeZ     = 0x04
+	TeleportRelativeYaw   = 0x08
+	TeleportRelativePitch = 0x10
+)
+
+// handleSyncPlayerPosition applies the server teleport and answers it the
+// way the vanilla client does: Confirm Teleportation followed by the new
+// position. Servers keep rejecting movement until the teleport is confirmed.
+func (c *Client) handleSyncPlayerPosition(pk proto.Packet) error {
+	var pos proto.SyncPlayerPositionResponse
+	if err := pk.Scan(&pos); err != nil {
+		return err
+	}
+
+	c.physics.mu.Lock()
+	var state PlayerState
+	c.Player.update(func(s *PlayerState) {
+		flags := byte(pos.Flags)
+		s.X = relative(flags&TeleportRelativeX != 0, s.X, float64(pos.X))
+		s.Y = relative(flags&TeleportRelativeY != 0, s.Y, float64(pos.Y))
+		s.Z = relative(flags&TeleportRelativeZ != 0, s.Z, float64(pos.Z))
+		s.Yaw = relative(flags&TeleportRelativeYaw != 0, s.Yaw, float32(pos.Yaw))
+		s.Pitch = relative(flags&TeleportRelativePitch != 0, s.Pitch, float32(pos.Pitch))
+		s.OnGround = false
+		state = *s
+	})
+	c.physics.reset(state)
+	c.physics.mu.Unlock()
+
+	if err := c.SendConfirmTeleportation(pos.TeleportID); err != nil {
+		return fmt.Errorf("cannot confirm teleportation: %w", err)
+	}
+	return c.SendPositionAndRotation(state.X, state.Y, state.Z, state.Yaw, state.Pitch, false)
+}
+
+func relative[T float32 | float64](isRelative bool, current, value T) T {
+	if isRelative {
+		return current + value
+	}
+	return value
+}
+
+func (c *Client) handlePlayerAbilities(pk proto.Packet) error {
+	var abilities proto.PlayerAbilitiesResponse
+	if err := pk.Scan(&abilities); err != nil {
+		return err
+	}
+
+	c.Player.update(func(state *PlayerState) {
+		state.Abilities = Abilities{
+			Flags:       byte(abilities.Flags),
+			FlyingSpeed: float32(abilities.FlyingSpeed),
+			FOVModifier: float32(abilities.FOVModifier),
+		}
+	})
+	return nil
+}
+
+func (c *Client) handleSetExperience(pk proto.Packet) error {
+	var xp proto.SetExperienceResponse
+	if err := pk.Scan(&xp); err != nil {
+		return err
+	}
+
+	c.Player.update(func(state *PlayerState) {
+		state.ExperienceBar = float32(xp.ExperienceBar)
+		state.Level = int32(xp.Level)
+		state.TotalExperience = int32(xp.TotalExperience)
+	})
+	return nil
+}
+
+func (c *Client) handleSetHeldItem(pk proto.Packet) error {
+	var held proto.SetHeldItemResponse
+	if err := pk.Scan(&held); err != nil {
+		return err
+	}
+
+	c.Player.update(func(state *PlayerState) {
+		state.HeldSlot = int8(held.Slot)
+	})
+	c.combat.reset()
 	return nil
 }
 
@@ -33,7 +151,7 @@ func (c *Client) handleDisconnect(pk proto.Packet) error {
 		return err
 	}
 
-	log.Printf("Player '%s' disconnected: %s\n", c.Player.Name, output.Reason)
+	log.Printf("Player '%s' disconnected: %s\n", c.Player.Name(), output.Reason)
 	return nil
 }
 
@@ -52,21 +170,13 @@ func (c *Client) handleSetHealthPacket(pk proto.Packet) error {
 		return err
 	}
 
-	log.Printf("[INFO] Health: %v", health)
-	if health.Health == 0 {
-		c.PerformRespawn()
-	}
-	return nil
-}
-
-func (c *Client) handleCombatDeathPacket(pk proto.Packet) error {
-	var death proto.CombatDeathResponse
-	if err := pk.Scan(&death); err != nil {
-		return err
-	}
+	c.Player.update(func(state *PlayerState) {
+		state.Health = float32(health.Health)
+		state.Food = int32(health.Food)
+		state.Saturation = float32(health.Saturation)
+	})
 
-	log.Printf("[INFO] Player '%s' died. msg: %v\n", c.Player.Name, death.Message)
-	c.PerformRespawn()
+	log.Printf("[INFO] Health: %v", health)
 	return nil
 }
 
@@ -77,5 +187,10 @@ func (c *Client) handleGameEvent(pk proto.Packet) error {
 	}
 
 	log.Printf("[INFO] Game Event: %v, %v", event.EventID, event.Value)
+	if event.EventID == GameEventChangeGameMode {
+		c.Player.update(func(state *PlayerState) {
+			state.GameMode = byte(event.Value)
+		})
+	}
 	return nil
 }
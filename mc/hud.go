@@ -0,0 +1,302 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"sync"
+	"time"
+)
+
+// Default title animation times in ticks.
+const (
+	defaultTitleFadeIn  = 10
+	defaultTitleStay    = 70
+	defaultTitleFadeOut = 20
+	// actionBarTicks is how long the action bar text stays on screen.
+	actionBarTicks = 60
+)
+
+// Bits of BossBar.Flags.
+const (
+	BossBarDarkenSky = 0x01
+	BossBarPlayMusic = 0x02
+	BossBarFog       = 0x04
+)
+
+type BossBar struct {
+	UUID     proto.Uuid
+	Title    proto.Component
+	Health   float32 // Health is from 0 to 1
+	Color    int32   // Color is 0 pink, 1 blue, 2 red, 3 green, 4 yellow, 5 purple or 6 white
+	Division int32   // Division is 0 for no notches, then 6, 10, 12 or 20 notches
+	Flags    byte
+}
+
+// Title is the text shown in the middle of the screen.
+type Title struct {
+	Title    proto.Component
+	Subtitle proto.Component // Subtitle is shown with the next title
+	FadeIn   int32           // FadeIn, Stay and FadeOut are in ticks
+	Stay     int32
+	FadeOut  int32
+	Shown    time.Time // Shown is when the title was set, zero if none
+}
+
+// Visible reports whether the title is still on screen at now.
+func (t Title) Visible(now time.Time) bool {
+	if t.Shown.IsZero() {
+		return false
+	}
+	return now.Sub(t.Shown) < time.Duration(t.FadeIn+t.Stay+t.FadeOut)*TickDuration
+}
+
+// BossBarEvent is emitted when a boss bar is added, changed or removed.
+type BossBarEvent struct {
+	BossBar BossBar
+	Removed bool
+}
+
+// TitleEvent is emitted when the title, subtitle or animation times change
+// and when titles are cleared.
+type TitleEvent struct {
+	Title Title
+}
+
+// ActionBarEvent is emitted for text shown above the hotbar, sent with Set
+// Action Bar Text or as an overlay system message.
+type ActionBarEvent struct {
+	Text proto.Component
+}
+
+// TabListEvent is emitted when the header or footer of the tab list change.
+type TabListEvent struct {
+	Header proto.Component
+	Footer proto.Component
+}
+
+// HUD tracks what the server shows on screen besides chat. It is safe for
+// concurrent use.
+type HUD struct {
+	mu        sync.RWMutex
+	bossBars  []*BossBar // bossBars are in the order they are shown
+	title     Title
+	actionBar proto.Component
+	actionAt  time.Time
+	header    proto.Component
+	footer    proto.Component
+}
+
+func newHUD() *HUD {
+	return &HUD{title: Title{FadeIn: defaultTitleFadeIn, Stay: defaultTitleStay, FadeOut: defaultTitleFadeOut}}
+}
+
+// BossBars returns the boss bars from the top of the screen.
+func (h *HUD) BossBars() []BossBar {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	out := make([]BossBar, len(h.bossBars))
+	for i, bar := range h.bossBars {
+		out[i] = *bar
+	}
+	return out
+}
+
+func (h *HUD) BossBar(uuid proto.Uuid) (BossBar, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if bar := h.bossBar(uuid); bar != nil {
+		return *bar, true
+	}
+	return BossBar{}, false
+}
+
+// bossBar returns the boss bar with uuid or nil. Caller must hold the lock.
+func (h *HUD) bossBar(uuid proto.Uuid) *BossBar {
+	for _, bar := range h.bossBars {
+		if bar.UUID == uuid {
+			return bar
+		}
+	}
+	return nil
+}
+
+// Title returns the last title, see Title.Visible.
+func (h *HUD) Title() Title {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.title
+}
+
+// ActionBar returns the action bar text while it is on screen.
+func (h *HUD) ActionBar() (proto.Component, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.actionAt.IsZero() || time.Since(h.actionAt) >= actionBarTicks*TickDuration {
+		return proto.Component{}, false
+	}
+	return h.actionBar, true
+}
+
+// TabList returns the header and footer of the tab list.
+func (h *HUD) TabList() (header, footer proto.Component) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.header, h.footer
+}
+
+func (c *Client) handleBossBar(pk proto.Packet) error {
+	var update proto.BossBarResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	h := c.HUD
+	h.mu.Lock()
+	bar := h.bossBar(update.UUID)
+	switch update.Action {
+	case proto.BossBarAdd:
+		if bar == nil {
+			bar = &BossBar{UUID: update.UUID}
+			h.bossBars = append(h.bossBars, bar)
+		}
+		bar.Title, _ = update.Title.Component()
+		bar.Health = float32(update.Health)
+		bar.Color, bar.Division = int32(update.Color), int32(update.Division)
+		bar.Flags = byte(update.Flags)
+	case proto.BossBarRemove:
+		for i, b := range h.bossBars {
+			if b == bar {
+				h.bossBars = append(h.bossBars[:i], h.bossBars[i+1:]...)
+				break
+			}
+		}
+	}
+	if bar != nil {
+		switch update.Action {
+		case proto.BossBarUpdateHealth:
+			bar.Health = float32(update.Health)
+		case proto.BossBarUpdateTitle:
+			bar.Title, _ = update.Title.Component()
+		case proto.BossBarUpdateStyle:
+			bar.Color, bar.Division = int32(update.Color), int32(update.Division)
+		case proto.BossBarUpdateFlags:
+			bar.Flags = byte(update.Flags)
+		}
+	}
+	var event BossBarEvent
+	if bar != nil {
+		event = BossBarEvent{BossBar: *bar, Removed: update.Action == proto.BossBarRemove}
+	}
+	h.mu.Unlock()
+
+	if bar != nil {
+		c.emit(event)
+	}
+	return nil
+}
+
+// updateTitle changes the title with fn and emits a TitleEvent.
+func (c *Client) updateTitle(fn func(title *Title)) {
+	h := c.HUD
+	h.mu.Lock()
+	fn(&h.title)
+	title := h.title
+	h.mu.Unlock()
+
+	c.emit(TitleEvent{Title: title})
+}
+
+func (c *Client) handleSetTitleText(pk proto.Packet) error {
+	var text proto.SetTitleTextResponse
+	if err := pk.Scan(&text); err != nil {
+		return err
+	}
+
+	component, _ := text.Text.Component()
+	c.updateTitle(func(title *Title) {
+		title.Title = component
+		title.Shown = time.Now()
+	})
+	return nil
+}
+
+func (c *Client) handleSetSubtitleText(pk proto.Packet) error {
+	var text proto.SetSubtitleTextResponse
+	if err := pk.Scan(&text); err != nil {
+		return err
+	}
+
+	component, _ := text.Text.Component()
+	c.updateTitle(func(title *Title) {
+		title.Subtitle = component
+	})
+	return nil
+}
+
+func (c *Client) handleSetTitleAnimationTimes(pk proto.Packet) error {
+	var times proto.SetTitleAnimationTimesResponse
+	if err := pk.Scan(&times); err != nil {
+		return err
+	}
+
+	c.updateTitle(func(title *Title) {
+		title.FadeIn, title.Stay, title.FadeOut = int32(times.FadeIn), int32(times.Stay), int32(times.FadeOut)
+	})
+	return nil
+}
+
+func (c *Client) handleClearTitles(pk proto.Packet) error {
+	var clear proto.ClearTitlesResponse
+	if err := pk.Scan(&clear); err != nil {
+		return err
+	}
+
+	c.updateTitle(func(title *Title) {
+		title.Title, title.Subtitle, title.Shown = proto.Component{}, proto.Component{}, time.Time{}
+		if clear.Reset {
+			title.FadeIn, title.Stay, title.FadeOut = defaultTitleFadeIn, defaultTitleStay, defaultTitleFadeOut
+		}
+	})
+	return nil
+}
+
+func (c *Client) handleSetActionBarText(pk proto.Packet) error {
+	var text proto.SetActionBarTextResponse
+	if err := pk.Scan(&text); err != nil {
+		return err
+	}
+
+	c.setActionBar(text.Text)
+	return nil
+}
+
+func (c *Client) setActionBar(text proto.Chat) {
+	component, _ := text.Component()
+
+	h := c.HUD
+	h.mu.Lock()
+	h.actionBar, h.actionAt = component, time.Now()
+	h.mu.Unlock()
+
+	c.emit(ActionBarEvent{Text: component})
+}
+
+func (c *Client) handleSetTabListHeaderAndFooter(pk proto.Packet) error {
+	var tab proto.SetTabListHeaderAndFooterResponse
+	if err := pk.Scan(&tab); err != nil {
+		return err
+	}
+
+	header, _ := tab.Header.Component()
+	footer, _ := tab.Footer.Component()
+
+	h := c.HUD
+	h.mu.Lock()
+	h.header, h.footer = header, footer
+	h.mu.Unlock()
+
+	c.emit(TabListEvent{Header: header, Footer: footer})
+	return nil
+}
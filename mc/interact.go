@@ -0,0 +1,77 @@
+package mc
+
+import (
+	"context"
+	"mc-bot/mc/proto"
+)
+
+// InteractEntity right-clicks the entity with the item held in hand, e.g.
+// to open a villager's trades, ride a horse or leash an animal.
+func (c *Client) InteractEntity(entityID int32, hand proto.VarInt) error {
+	entity, ok := c.Entities.Get(entityID)
+	if !ok {
+		return ErrUnknownEntity
+	}
+
+	box := entity.Box(c.entityTypes().EntityType(entity.Type))
+	if !c.entityInReach(box) {
+		return ErrOutOfReach
+	}
+
+	c.LookAt(entity.X, (box.MinY+box.MaxY)/2, entity.Z)
+	if err := c.sendLook(); err != nil {
+		return err
+	}
+	return c.sendInteract(entityID, InteractTypeInteract, nil, hand)
+}
+
+// InteractAt right-clicks a point of the entity relative to its position,
+// which armor stands use to pick the slot. It always follows up with a plain
+// interaction: the vanilla client only does so when the point was not used,
+// which the bot cannot tell without simulating the entity.
+func (c *Client) InteractAt(entityID int32, hand proto.VarInt, x, y, z float32) error {
+	entity, ok := c.Entities.Get(entityID)
+	if !ok {
+		return ErrUnknownEntity
+	}
+
+	box := entity.Box(c.entityTypes().EntityType(entity.Type))
+	if !c.entityInReach(box) {
+		return ErrOutOfReach
+	}
+
+	c.LookAt(entity.X+float64(x), entity.Y+float64(y), entity.Z+float64(z))
+	if err := c.sendLook(); err != nil {
+		return err
+	}
+	if err := c.sendInteract(entityID, InteractTypeAt, &[3]float32{x, y, z}, hand); err != nil {
+		return err
+	}
+	return c.sendInteract(entityID, InteractTypeInteract, nil, hand)
+}
+
+// ActivateBlock right-clicks the block at pos on the face looking at the
+// player, flipping levers, pressing buttons, opening doors and containers.
+// It waits for the server to process the click. While sneaking the held
+// item is used on the block instead.
+func (c *Client) ActivateBlock(ctx context.Context, pos proto.Position) error {
+	if !c.inReach(pos) {
+		return ErrOutOfReach
+	}
+
+	face := c.facingFace(pos)
+	cx, cy, cz := faceCenter(face)
+	c.LookAt(float64(pos.X)+float64(cx), float64(pos.Y)+float64(cy), float64(pos.Z)+float64(cz))
+	if err := c.sendLook(); err != nil {
+		return err
+	}
+
+	sequence, err := c.useItemOn(HandMain, pos, face, cx, cy, cz)
+	if err != nil {
+		return err
+	}
+	if err := c.SwingArm(HandMain); err != nil {
+		return err
+	}
+	return c.waitAcknowledged(ctx, sequence)
+}
@@ -0,0 +1,452 @@
+package mc
+
+import (
+	"context"
+	"fmt"
+	"mc-bot/mc/proto"
+	"sort"
+	"sync"
+)
+
+// Slots of the player inventory window, see
+// https://wiki.vg/index.php?title=Inventory&oldid=18375#Player_Inventory
+const (
+	SlotCraftingResult = 0
+	SlotCraftingInput  = 1 // SlotCraftingInput is the first of 4 crafting grid slots
+	SlotHelmet         = 5
+	SlotChestplate     = 6
+	SlotLeggings       = 7
+	SlotBoots          = 8
+	SlotMain           = 9  // SlotMain is the first of 27 main inventory slots
+	SlotHotbar         = 36 // SlotHotbar is the first of 9 hotbar slots
+	SlotOffhand        = 45
+
+	playerInventorySize = 46
+	// playerSlots is the number of main inventory and hotbar slots placed at
+	// the end of every container window.
+	playerSlots = 36
+)
+
+// Window types of the minecraft:menu registry.
+const (
+	MenuCrafting = 11 // MenuCrafting is the window type of crafting tables
+	MenuMerchant = 18 // MenuMerchant is the window type of villager trades
+)
+
+// PlayerWindowID is the ID of the player inventory window, which is always
+// open.
+const PlayerWindowID = 0
+
+// Click modes of Click Container, see
+// https://wiki.vg/index.php?title=Protocol&oldid=18375#Click_Container
+type ClickMode int32
+
+const (
+	ClickPickup ClickMode = iota
+	ClickQuickMove
+	ClickSwap
+	ClickClone
+	ClickThrow
+	ClickQuickCraft
+	ClickPickupAll
+)
+
+// SlotOutside is the slot index of clicks outside of the window.
+const SlotOutside = -999
+
+// Window is a snapshot of an inventory window.
+type Window struct {
+	ID         int32
+	Type       int32 // Type is an ID from the minecraft:menu registry, -1 for the player inventory
+	Title      proto.Chat
+	Slots      []proto.Slot
+	Properties map[int16]int16
+}
+
+func (w *Window) clone() Window {
+	out := *w
+	out.Slots = append([]proto.Slot(nil), w.Slots...)
+	out.Properties = make(map[int16]int16, len(w.Properties))
+	for k, v := range w.Properties {
+		out.Properties[k] = v
+	}
+	return out
+}
+
+// playerOffset returns the index of the first main inventory slot in the
+// window. Slots from there on mirror SlotMain and following player slots.
+func (w *Window) playerOffset() int {
+	if w.ID == PlayerWindowID {
+		return SlotMain
+	}
+	return len(w.Slots) - playerSlots
+}
+
+// Inventory tracks the player inventory and the opened container. It is safe
+// for concurrent use.
+type Inventory struct {
+	mu      sync.Mutex
+	player  *Window
+	open    *Window // open is nil when no container is open
+	carried proto.Slot
+	stateID int32
+	client  *Client // client sends the clicks of Move, set by Client.Connect
+}
+
+func newInventory() *Inventory {
+	return &Inventory{player: newPlayerWindow()}
+}
+
+func newPlayerWindow() *Window {
+	return &Window{
+		ID:         PlayerWindowID,
+		Type:       -1,
+		Slots:      make([]proto.Slot, playerInventorySize),
+		Properties: map[int16]int16{},
+	}
+}
+
+// Player returns the player inventory window.
+func (inv *Inventory) Player() Window {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.player.clone()
+}
+
+// Open returns the opened container, ok is false when only the player
+// inventory is open.
+func (inv *Inventory) Open() (window Window, ok bool) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if inv.open == nil {
+		return Window{}, false
+	}
+	return inv.open.clone(), true
+}
+
+// Current returns the opened container or the player inventory when no
+// container is open. Slot indexes of the item helpers refer to this window.
+func (inv *Inventory) Current() Window {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.current().clone()
+}
+
+// current returns the window the player interacts with. Caller must hold the
+// lock.
+func (inv *Inventory) current() *Window {
+	if inv.open != nil {
+		return inv.open
+	}
+	return inv.player
+}
+
+// Slot returns a slot of the player inventory window.
+func (inv *Inventory) Slot(i int) proto.Slot {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if i < 0 || i >= len(inv.player.Slots) {
+		return proto.Slot{}
+	}
+	return inv.player.Slots[i]
+}
+
+// Carried returns the item held by the mouse cursor.
+func (inv *Inventory) Carried() proto.Slot {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.carried
+}
+
+// window returns the window with given ID. Caller must hold the lock.
+func (inv *Inventory) window(id int32) *Window {
+	switch {
+	case id == PlayerWindowID:
+		return inv.player
+	case inv.open != nil && inv.open.ID == id:
+		return inv.open
+	}
+	return nil
+}
+
+// setSlot changes a slot and keeps player slots of the player inventory and
+// the opened container in sync. Caller must hold the lock.
+func (inv *Inventory) setSlot(w *Window, i int, slot proto.Slot) {
+	if i < 0 || i >= len(w.Slots) {
+		return
+	}
+	w.Slots[i] = slot
+
+	offset := w.playerOffset()
+	if offset < 0 || i < offset {
+		return
+	}
+
+	mirror := inv.open
+	if w != inv.player {
+		mirror = inv.player
+	}
+	if mirror != nil {
+		if j := mirror.playerOffset() + i - offset; j >= 0 && j < len(mirror.Slots) {
+			mirror.Slots[j] = slot
+		}
+	}
+}
+
+// HeldItem returns the item in the selected hotbar slot.
+func (c *Client) HeldItem() proto.Slot {
+	return c.Inventory.Slot(SlotHotbar + int(c.Player.HeldSlot()))
+}
+
+// waitSlot waits until cond accepts slot i of the current window, giving up
+// after ackTimeout.
+func (c *Client) waitSlot(ctx context.Context, i int, cond func(proto.Slot) bool) error {
+	changed := make(chan struct{}, 1)
+	unsubscribe := c.Subscribe(func(event Event) {
+		if _, ok := event.(InventoryChangedEvent); ok {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	})
+	defer unsubscribe()
+
+	ctx, cancel := context.WithTimeout(ctx, ackTimeout)
+	defer cancel()
+	for {
+		if window := c.Inventory.Current(); i < len(window.Slots) && cond(window.Slots[i]) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// stowCarried puts the item held by the cursor into the player slots of the
+// current window, merging it with equal items first.
+func (c *Client) stowCarried() error {
+	for {
+		carried := c.Inventory.Carried()
+		if carried.Empty() {
+			return nil
+		}
+
+		window := c.Inventory.Current()
+		start, end := window.playerOffset(), len(window.Slots)
+		if window.ID == PlayerWindowID {
+			end = SlotOffhand
+		}
+
+		target := -1
+		for i := start; i < end && target < 0; i++ {
+			slot := window.Slots[i]
+			if !slot.Empty() && sameItem(slot, carried) && int(slot.Count) < maxStack(c.items(), slot) {
+				target = i
+			}
+		}
+		for i := start; i < end && target < 0; i++ {
+			if window.Slots[i].Empty() {
+				target = i
+			}
+		}
+		if target < 0 {
+			return ErrInventoryFull
+		}
+
+		if err := c.click(target, 0, ClickPickup); err != nil {
+			return err
+		}
+	}
+}
+
+// ClickContainer sends a click in window on slot. The outcome of a click
+// is predicted by the caller: changed holds the new content of every slot
+// the click modified and carried the item held by the cursor afterwards.
+// The prediction is applied locally right away; if it was wrong, the
+// server sends the whole window back.
+func (c *Client) ClickContainer(windowID int32, slot int16, button int8, mode ClickMode, changed map[int16]proto.Slot, carried proto.Slot) error {
+	inv := c.Inventory
+	inv.mu.Lock()
+	w := inv.window(windowID)
+	if w == nil {
+		inv.mu.Unlock()
+		return ErrWindowClosed
+	}
+
+	request := RequestClickContainer{
+		WindowID: proto.UByte(windowID),
+		StateID:  proto.VarInt(inv.stateID),
+		Slot:     proto.Short(slot),
+		Button:   proto.Byte(button),
+		Mode:     proto.VarInt(mode),
+		Carried:  carried,
+	}
+	for i, item := range changed {
+		request.Changed = append(request.Changed, ChangedSlot{Slot: proto.Short(i), Data: item})
+		inv.setSlot(w, int(i), item)
+	}
+	// keep the packet deterministic
+	sort.Slice(request.Changed, func(i, j int) bool {
+		return request.Changed[i].Slot < request.Changed[j].Slot
+	})
+	inv.carried = carried
+	inv.mu.Unlock()
+
+	packet := proto.NewPacket(0x0b)
+	if err := packet.Append(&request); err != nil {
+		return fmt.Errorf("cannot append click container request: %w", err)
+	}
+
+	if err := c.SendPacket(packet); err != nil {
+		return err
+	}
+	c.emit(InventoryChangedEvent{WindowID: windowID, Slot: -1})
+	return nil
+}
+
+// CloseContainer closes the opened container. The server moves the carried
+// item back to the inventory.
+func (c *Client) CloseContainer() error {
+	inv := c.Inventory
+	inv.mu.Lock()
+	if inv.open == nil {
+		inv.mu.Unlock()
+		return ErrWindowClosed
+	}
+	id := inv.open.ID
+	inv.open = nil
+	inv.carried = proto.Slot{}
+	inv.mu.Unlock()
+
+	packet := proto.NewPacket(0x0c)
+	if err := packet.Append(&RequestCloseContainer{WindowID: proto.UByte(id)}); err != nil {
+		return err
+	}
+
+	if err := c.SendPacket(packet); err != nil {
+		return err
+	}
+	c.emit(ContainerClosedEvent{WindowID: id})
+	return nil
+}
+
+func (c *Client) handleOpenScreen(pk proto.Packet) error {
+	var screen proto.OpenScreenResponse
+	if err := pk.Scan(&screen); err != nil {
+		return err
+	}
+
+	inv := c.Inventory
+	inv.mu.Lock()
+	inv.open = &Window{
+		ID:         int32(screen.WindowID),
+		Type:       int32(screen.WindowType),
+		Title:      screen.Title,
+		Properties: map[int16]int16{},
+	}
+	window := inv.open.clone()
+	inv.mu.Unlock()
+
+	c.emit(ContainerOpenedEvent{Window: window})
+	return nil
+}
+
+func (c *Client) handleCloseContainer(pk proto.Packet) error {
+	var closed proto.CloseContainerResponse
+	if err := pk.Scan(&closed); err != nil {
+		return err
+	}
+
+	inv := c.Inventory
+	inv.mu.Lock()
+	if inv.open != nil && inv.open.ID == int32(closed.WindowID) {
+		inv.open = nil
+		inv.carried = proto.Slot{}
+	}
+	inv.mu.Unlock()
+
+	c.emit(ContainerClosedEvent{WindowID: int32(closed.WindowID)})
+	return nil
+}
+
+func (c *Client) handleSetContainerContent(pk proto.Packet) error {
+	var content proto.SetContainerContentResponse
+	if err := pk.Scan(&content); err != nil {
+		return err
+	}
+
+	inv := c.Inventory
+	inv.mu.Lock()
+	w := inv.window(int32(content.WindowID))
+	if w == nil {
+		inv.mu.Unlock()
+		return nil
+	}
+
+	inv.stateID = int32(content.StateID)
+	inv.carried = content.Carried
+	// the size of a container is only known from its first content
+	if len(w.Slots) != len(content.Slots) && w != inv.player {
+		w.Slots = make([]proto.Slot, len(content.Slots))
+	}
+	for i, slot := range content.Slots {
+		inv.setSlot(w, i, slot)
+	}
+	inv.mu.Unlock()
+
+	c.emit(InventoryChangedEvent{WindowID: int32(content.WindowID), Slot: -1})
+	return nil
+}
+
+func (c *Client) handleSetContainerSlot(pk proto.Packet) error {
+	var update proto.SetContainerSlotResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	inv := c.Inventory
+	inv.mu.Lock()
+	windowID := int32(update.WindowID)
+	switch windowID {
+	case -1:
+		update.Slot = -1
+		inv.carried = update.Data
+	case -2:
+		windowID = PlayerWindowID
+		inv.setSlot(inv.player, int(update.Slot), update.Data)
+	default:
+		w := inv.window(windowID)
+		if w == nil {
+			inv.mu.Unlock()
+			return nil
+		}
+		inv.stateID = int32(update.StateID)
+		inv.setSlot(w, int(update.Slot), update.Data)
+	}
+	inv.mu.Unlock()
+
+	c.emit(InventoryChangedEvent{WindowID: windowID, Slot: int16(update.Slot)})
+	return nil
+}
+
+func (c *Client) handleSetContainerProperty(pk proto.Packet) error {
+	var property proto.SetContainerPropertyResponse
+	if err := pk.Scan(&property); err != nil {
+		return err
+	}
+
+	inv := c.Inventory
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if w := inv.window(int32(property.WindowID)); w != nil {
+		w.Properties[int16(property.Property)] = int16(property.Value)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package mc
+
+import "mc-bot/mc/proto"
+
+type ToolType byte
+
+const (
+	ToolNone ToolType = iota
+	ToolPickaxe
+	ToolAxe
+	ToolShovel
+	ToolHoe
+	ToolSword
+	ToolShears
+)
+
+// Tool tiers compared with Block.HarvestTier.
+const (
+	TierWood = iota // TierWood is also the tier of golden tools
+	TierStone
+	TierIron
+	TierDiamond
+	TierNetherite
+)
+
+// Item describes an item type.
+type Item struct {
+	Name      string // Name is e.g. "minecraft:diamond_pickaxe", empty when not known
+	MaxStack  int
+	Tool      ToolType
+	ToolTier  int
+	ToolSpeed float32 // ToolSpeed is the mining speed multiplier of tools
+	ArmorSlot int     // ArmorSlot is the player inventory slot the item is worn in, 0 if none
+
+	Food       int     // Food is the hunger restored by eating the item, 0 if it is not food
+	Saturation float32 // Saturation is the saturation restored by eating the item
+	EatTicks   int     // EatTicks is how long eating takes, 32 ticks when 0
+}
+
+// ItemRegistry describes item IDs. Like BlockRegistry it has to be filled
+// from data generated for the server version.
+type ItemRegistry interface {
+	Item(id int32) Item
+}
+
+// DefaultItems is used when Client.Items is nil. It knows nothing about
+// items and treats all of them as plain stackable items.
+var DefaultItems ItemRegistry = defaultItems{}
+
+type defaultItems struct{}
+
+func (defaultItems) Item(id int32) Item {
+	return Item{MaxStack: 64}
+}
+
+func (c *Client) items() ItemRegistry {
+	if c.Items == nil {
+		return DefaultItems
+	}
+	return c.Items
+}
+
+// Enchantment returns the level of enchantment id (e.g. "minecraft:efficiency")
+// on the item in slot or 0 if it does not have it.
+func Enchantment(slot proto.Slot, id string) int {
+	list, _ := slot.NBT.Compound()["Enchantments"].([]any)
+	for _, e := range list {
+		enchantment, _ := e.(map[string]any)
+		if enchantment["id"] != id {
+			continue
+		}
+
+		switch lvl := enchantment["lvl"].(type) {
+		case int16:
+			return int(lvl)
+		case int32:
+			return int(lvl)
+		case int8:
+			return int(lvl)
+		}
+	}
+	return 0
+}
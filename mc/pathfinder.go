@@ -0,0 +1,391 @@
+package mc
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math"
+	"mc-bot/mc/proto"
+	"sync"
+	"time"
+)
+
+const (
+	// maxSafeDrop is the highest drop without fall damage.
+	maxSafeDrop = 3
+	// maxPathNodes limits how many nodes the search visits before giving up.
+	maxPathNodes = 20000
+	// waypointReach is the horizontal distance from a node center at which
+	// the node counts as reached.
+	waypointReach = 0.3
+	stuckTimeout  = 3 * time.Second
+	maxStuck      = 5
+
+	costWalk     = 1.0
+	costDiagonal = math.Sqrt2
+	costJump     = 2.0
+	costDrop     = 0.5 // costDrop is added per block fallen
+	costClimb    = 1.5
+	costSwim     = 2.0
+	costDoor     = 1.0 // costDoor is added for opening a closed door
+)
+
+// errReplan stops walking a path that is no longer valid.
+var errReplan = errors.New("path changed")
+
+// PathStatus reports progress of Goto. The last status sent before the
+// channel is closed has either Done or Err set.
+type PathStatus struct {
+	Position  proto.Position // Position is the last reached node
+	Remaining int            // Remaining is the number of nodes left to the target
+	Done      bool
+	Err       error
+}
+
+// Goto walks the player to target, which is the block the feet should end
+// up in. The path is planned with A* over loaded chunks and re-planned when
+// a block on it changes. RunPhysics must be running for the player to move
+// and Client.Blocks must be set. Closed doors on the way are opened.
+// The returned channel must be drained until it is closed.
+func (c *Client) Goto(ctx context.Context, target proto.Position) <-chan PathStatus {
+	status := make(chan PathStatus, 16)
+	go func() {
+		defer close(status)
+		if err := c.followPath(ctx, target, status); err != nil {
+			status <- PathStatus{Position: feetPosition(c.Player.State()), Err: err}
+			return
+		}
+		status <- PathStatus{Position: target, Done: true}
+	}()
+	return status
+}
+
+// FindPath returns the nodes from start to target including both ends.
+// Client.Blocks must be set.
+func (c *Client) FindPath(start, target proto.Position) ([]proto.Position, error) {
+	if c.Blocks == nil {
+		return nil, ErrNoBlockRegistry
+	}
+
+	open := &pathHeap{}
+	nodes := map[proto.Position]*pathNode{}
+
+	first := &pathNode{pos: start, f: distance(start, target)}
+	nodes[start] = first
+	heap.Push(open, first)
+
+	for visited := 0; open.Len() > 0 && visited < maxPathNodes; visited++ {
+		node := heap.Pop(open).(*pathNode)
+		node.closed = true
+		if node.pos == target {
+			return node.path(), nil
+		}
+
+		for _, move := range c.pathMoves(node.pos) {
+			g := node.g + move.cost
+			next, ok := nodes[move.to]
+			if ok && (next.closed || g >= next.g) {
+				continue
+			}
+			if !ok {
+				next = &pathNode{pos: move.to}
+				nodes[move.to] = next
+			}
+
+			next.g, next.f, next.parent = g, g+distance(move.to, target), node
+			if ok && next.index >= 0 {
+				heap.Fix(open, next.index)
+			} else {
+				heap.Push(open, next)
+			}
+		}
+	}
+	return nil, ErrNoPath
+}
+
+func (c *Client) followPath(ctx context.Context, target proto.Position, status chan<- PathStatus) error {
+	var mu sync.Mutex
+	var path []proto.Position
+	replan := make(chan struct{}, 1)
+
+	unsubscribe := c.Subscribe(func(event Event) {
+		changed, ok := event.(BlockChangedEvent)
+		if !ok {
+			return
+		}
+
+		mu.Lock()
+		affected := pathAffected(path, changed.Position)
+		mu.Unlock()
+		if affected {
+			select {
+			case replan <- struct{}{}:
+			default:
+			}
+		}
+	})
+	defer unsubscribe()
+	defer c.SetControls(Controls{})
+
+	for stuck := 0; ; {
+		found, err := c.FindPath(feetPosition(c.Player.State()), target)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		path = found
+		mu.Unlock()
+
+		err = c.walkPath(ctx, found, replan, status)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, ErrStuck):
+			if stuck++; stuck >= maxStuck {
+				return err
+			}
+		case !errors.Is(err, errReplan):
+			return err
+		}
+	}
+}
+
+// walkPath steers the player along path one node at a time.
+func (c *Client) walkPath(ctx context.Context, path []proto.Position, replan <-chan struct{}, status chan<- PathStatus) error {
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+
+	opened := map[proto.Position]bool{}
+	lastProgress := time.Now()
+	for i := 1; i < len(path); {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-replan:
+			return errReplan
+		case <-ticker.C:
+		}
+
+		state := c.Player.State()
+		next := path[i]
+		dx, dz := float64(next.X)+0.5-state.X, float64(next.Z)+0.5-state.Z
+		horizontal := math.Hypot(dx, dz)
+
+		if horizontal < waypointReach && state.Y >= float64(next.Y)-0.01 && state.Y < float64(next.Y)+1 {
+			i++
+			lastProgress = time.Now()
+			select {
+			case status <- PathStatus{Position: next, Remaining: len(path) - i}:
+			default:
+			}
+			continue
+		}
+
+		if time.Since(lastProgress) > stuckTimeout {
+			return ErrStuck
+		}
+
+		for _, pos := range []proto.Position{next, up(next)} {
+			if block := c.block(pos); block.Openable && !block.Open && !opened[pos] {
+				// doors further away are opened once the player gets closer
+				err := c.ActivateBlock(ctx, pos)
+				if errors.Is(err, ErrOutOfReach) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				opened[pos] = true
+			}
+		}
+
+		feet := feetPosition(state)
+		inWater := c.block(feet).Fluid == FluidWater
+		controls := Controls{
+			Forward: math.Min(1, horizontal*2+0.2),
+			Jump:    next.Y > feet.Y || (inWater && next.Y >= feet.Y),
+		}
+		if horizontal < 0.1 {
+			controls.Forward = 0
+		} else {
+			yaw := -math.Atan2(dx, dz) * 180 / math.Pi
+			c.Look(float32(yaw), 0)
+		}
+		c.SetControls(controls)
+	}
+	return nil
+}
+
+// pathAffected reports whether a block change at pos can invalidate path.
+func pathAffected(path []proto.Position, pos proto.Position) bool {
+	for _, node := range path {
+		if node == pos || up(node) == pos || down(node) == pos {
+			return true
+		}
+	}
+	return false
+}
+
+type pathMove struct {
+	to   proto.Position
+	cost float64
+}
+
+// pathMoves returns the nodes reachable from pos in one move.
+func (c *Client) pathMoves(pos proto.Position) []pathMove {
+	var moves []pathMove
+	for _, d := range [][2]int32{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}} {
+		n := proto.Position{X: pos.X + d[0], Y: pos.Y, Z: pos.Z + d[1]}
+		diagonal := d[0] != 0 && d[1] != 0
+
+		if diagonal {
+			// do not cut corners, both sides have to be free
+			side1 := proto.Position{X: pos.X + d[0], Y: pos.Y, Z: pos.Z}
+			side2 := proto.Position{X: pos.X, Y: pos.Y, Z: pos.Z + d[1]}
+			if !c.passable(side1) || !c.passable(up(side1)) || !c.passable(side2) || !c.passable(up(side2)) {
+				continue
+			}
+			if c.standable(n) && !c.needsDoor(n) {
+				moves = append(moves, pathMove{n, costDiagonal})
+			}
+			continue
+		}
+
+		if c.standable(n) {
+			cost := costWalk
+			if c.needsDoor(n) {
+				cost += costDoor
+			}
+			moves = append(moves, pathMove{n, cost})
+			continue
+		}
+
+		// jump on a block in front, there has to be room above the head
+		if jump := up(n); c.standable(jump) && c.passable(up(up(pos))) && !c.needsDoor(jump) {
+			moves = append(moves, pathMove{jump, costJump})
+			continue
+		}
+
+		// walk off an edge and fall
+		if !c.passable(n) || !c.passable(up(n)) {
+			continue
+		}
+		for fall := int32(1); ; fall++ {
+			below := proto.Position{X: n.X, Y: n.Y - fall, Z: n.Z}
+			if !c.passable(below) || !c.World.Loaded(below.X, below.Z) {
+				break
+			}
+			if c.standable(below) {
+				if fall <= maxSafeDrop || c.block(below).Fluid == FluidWater {
+					moves = append(moves, pathMove{below, costWalk + float64(fall)*costDrop})
+				}
+				break
+			}
+		}
+	}
+
+	// ladders and water allow moving straight up and down
+	block := c.block(pos)
+	if block.Climbable || block.Fluid == FluidWater {
+		cost := costClimb
+		if block.Fluid == FluidWater {
+			cost = costSwim
+		}
+		if u := up(pos); c.standable(u) {
+			moves = append(moves, pathMove{u, cost})
+		}
+		if d := down(pos); c.standable(d) {
+			moves = append(moves, pathMove{d, cost})
+		}
+	} else if d := down(pos); c.standable(d) && (c.block(d).Climbable || c.block(d).Fluid == FluidWater) {
+		moves = append(moves, pathMove{d, costClimb})
+	}
+	return moves
+}
+
+// passable reports whether the player can be inside the block, possibly
+// after opening it. Ladders have a thin collision shape but are climbed
+// from inside the block.
+func (c *Client) passable(pos proto.Position) bool {
+	block := c.block(pos)
+	return block.Fluid != FluidLava && (!block.Solid() || block.Openable || block.Climbable)
+}
+
+// standable reports whether the player can stay with feet in the block.
+func (c *Client) standable(pos proto.Position) bool {
+	if !c.World.Loaded(pos.X, pos.Z) || !c.passable(pos) || !c.passable(up(pos)) {
+		return false
+	}
+
+	block := c.block(pos)
+	if block.Climbable || block.Fluid == FluidWater {
+		return true
+	}
+
+	floor := c.block(down(pos))
+	return floor.Solid() && !floor.Openable
+}
+
+// needsDoor reports whether a door has to be opened to stand at pos.
+func (c *Client) needsDoor(pos proto.Position) bool {
+	feet, head := c.block(pos), c.block(up(pos))
+	return (feet.Openable && !feet.Open) || (head.Openable && !head.Open)
+}
+
+func up(pos proto.Position) proto.Position {
+	return proto.Position{X: pos.X, Y: pos.Y + 1, Z: pos.Z}
+}
+
+func down(pos proto.Position) proto.Position {
+	return proto.Position{X: pos.X, Y: pos.Y - 1, Z: pos.Z}
+}
+
+func distance(a, b proto.Position) float64 {
+	dx, dy, dz := float64(a.X-b.X), float64(a.Y-b.Y), float64(a.Z-b.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+type pathNode struct {
+	pos    proto.Position
+	g, f   float64
+	parent *pathNode
+	closed bool
+	index  int // index in pathHeap, -1 when not queued
+}
+
+func (n *pathNode) path() []proto.Position {
+	var out []proto.Position
+	for node := n; node != nil; node = node.parent {
+		out = append(out, node.pos)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// pathHeap is the A* open set ordered by f.
+type pathHeap []*pathNode
+
+func (h pathHeap) Len() int           { return len(h) }
+func (h pathHeap) Less(i, j int) bool { return h[i].f < h[j].f }
+
+func (h pathHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *pathHeap) Push(x any) {
+	node := x.(*pathNode)
+	node.index = len(*h)
+	*h = append(*h, node)
+}
+
+func (h *pathHeap) Pop() any {
+	old := *h
+	node := old[len(old)-1]
+	node.index = -1
+	*h = old[:len(old)-1]
+	return node
+}
@@ -0,0 +1,622 @@
+package mc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"mc-bot/mc/proto"
+	"sync"
+	"time"
+)
+
+// TickDuration is the length of a game tick, the game runs at 20 TPS.
+const TickDuration = 50 * time.Millisecond
+
+// Constants of the vanilla player movement, see LivingEntity#travel.
+const (
+	playerWidth          = 0.6
+	playerHeight         = 1.8
+	playerSneakHeight    = 1.5
+	playerStepHeight     = 0.6
+	playerEyeHeight      = 1.62
+	playerSneakEyeHeight = 1.27
+	gravity              = 0.08
+	airDrag              = 0.98
+	defaultSlipperiness  = 0.6
+	jumpVelocity         = 0.42
+	sprintJumpBoost      = 0.2
+	sprintMultiplier     = 1.3
+	sneakMultiplier      = 0.3
+	defaultMoveSpeed     = 0.1
+
+	// positionThreshold is the squared distance the player has to move
+	// before the client sends its position again.
+	positionThreshold = 2e-4 * 2e-4
+	// idlePositionTicks is how often the position is sent when standing still.
+	idlePositionTicks = 20
+)
+
+// sprintModifierUUID is the movement speed modifier the game adds while
+// sprinting. The server echoes it in Update Attributes, but physics applies
+// sprinting on its own so that speed follows the local sprint state.
+var sprintModifierUUID = *proto.NewUuidFromStr("662a6b8d-da3e-4c1c-8813-96ea6097278d")
+
+// Controls are the movement inputs applied on every physics tick, like keys
+// held by a player.
+type Controls struct {
+	Forward float64 // Forward is 1 to walk forward, -1 to walk backward
+	Strafe  float64 // Strafe is 1 to walk left, -1 to walk right
+	Jump    bool
+	Sprint  bool
+	Sneak   bool
+}
+
+type physics struct {
+	mu       sync.Mutex
+	controls Controls
+
+	ready                  bool // ready is set once the server sent the position
+	velX, velY, velZ       float64
+	horizontalCollision    bool
+	sprinting, sneaking    bool
+	lastX, lastY, lastZ    float64
+	lastYaw, lastPitch     float32
+	lastOnGround           bool
+	ticksSincePositionSent int
+}
+
+// SetControls replaces the movement inputs used by RunPhysics.
+func (c *Client) SetControls(controls Controls) {
+	c.physics.mu.Lock()
+	defer c.physics.mu.Unlock()
+	c.physics.controls = controls
+}
+
+func (c *Client) Controls() Controls {
+	c.physics.mu.Lock()
+	defer c.physics.mu.Unlock()
+	return c.physics.controls
+}
+
+// Velocity returns the current velocity in blocks per tick.
+func (c *Client) Velocity() (x, y, z float64) {
+	c.physics.mu.Lock()
+	defer c.physics.mu.Unlock()
+	return c.physics.velX, c.physics.velY, c.physics.velZ
+}
+
+// Look turns the player. Yaw is in degrees with 0 facing south (+Z), pitch
+// is in degrees with -90 looking up.
+func (c *Client) Look(yaw, pitch float32) {
+	c.Player.update(func(state *PlayerState) {
+		state.Yaw = yaw
+		state.Pitch = float32(math.Max(-90, math.Min(90, float64(pitch))))
+	})
+}
+
+// LookAt turns the player so that its eyes face given point.
+func (c *Client) LookAt(x, y, z float64) {
+	state := c.Player.State()
+	dx, dy, dz := x-state.X, y-state.eyeY(), z-state.Z
+	yaw := -math.Atan2(dx, dz) * 180 / math.Pi
+	pitch := -math.Atan2(dy, math.Hypot(dx, dz)) * 180 / math.Pi
+	c.Look(float32(yaw), float32(pitch))
+}
+
+// RunPhysics simulates player movement at 20 TPS and sends the resulting
+// position to the server the way the vanilla client does. It blocks until
+// ctx is done or sending fails. Client.Blocks must be set.
+func (c *Client) RunPhysics(ctx context.Context) error {
+	if c.Blocks == nil {
+		return ErrNoBlockRegistry
+	}
+
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := c.physicsTick(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reset stops movement after the server moved the player. Caller must hold
+// p.mu while also updating the player position, otherwise a running tick
+// could overwrite the new position.
+func (p *physics) reset(state PlayerState) {
+	p.ready = true
+	p.velX, p.velY, p.velZ = 0, 0, 0
+	p.lastX, p.lastY, p.lastZ = state.X, state.Y, state.Z
+	p.lastYaw, p.lastPitch = state.Yaw, state.Pitch
+	p.lastOnGround = false
+	p.ticksSincePositionSent = 0
+}
+
+func (c *Client) physicsTick() error {
+	p := c.physics
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.ready {
+		return nil
+	}
+
+	state := c.Player.State()
+	if err := c.sendMovementCommands(state); err != nil {
+		return err
+	}
+
+	if c.World.Loaded(int32(math.Floor(state.X)), int32(math.Floor(state.Z))) {
+		c.travel(&state)
+		c.Player.update(func(s *PlayerState) {
+			s.X, s.Y, s.Z = state.X, state.Y, state.Z
+			s.OnGround, s.Pose = state.OnGround, state.Pose
+		})
+	}
+	return c.sendPosition(state)
+}
+
+// sendMovementCommands tells the server when the player starts or stops
+// sprinting and sneaking. Caller must hold p.mu.
+func (c *Client) sendMovementCommands(state PlayerState) error {
+	p := c.physics
+	ctrl := p.controls
+
+	sneaking := ctrl.Sneak
+	if sneaking != p.sneaking {
+		action := PlayerCommandStopSneaking
+		if sneaking {
+			action = PlayerCommandStartSneaking
+		}
+		if err := c.SendPlayerCommand(action); err != nil {
+			return err
+		}
+		p.sneaking = sneaking
+	}
+
+	// the game stops sprinting when hungry, walking backwards, sneaking
+	// or running into a wall
+	sprinting := ctrl.Sprint && ctrl.Forward > 0 && !ctrl.Sneak && !p.horizontalCollision &&
+		(state.Food > 6 || state.Abilities.Flags&AbilityAllowFlying != 0)
+	if sprinting != p.sprinting {
+		action := PlayerCommandStopSprinting
+		if sprinting {
+			action = PlayerCommandStartSprinting
+		}
+		if err := c.SendPlayerCommand(action); err != nil {
+			return err
+		}
+		p.sprinting = sprinting
+	}
+	return nil
+}
+
+// sendPosition sends the cheapest packet describing the change since the
+// last tick. Caller must hold p.mu.
+func (c *Client) sendPosition(state PlayerState) error {
+	p := c.physics
+	p.ticksSincePositionSent++
+
+	dx, dy, dz := state.X-p.lastX, state.Y-p.lastY, state.Z-p.lastZ
+	moved := dx*dx+dy*dy+dz*dz > positionThreshold || p.ticksSincePositionSent >= idlePositionTicks
+	rotated := state.Yaw != p.lastYaw || state.Pitch != p.lastPitch
+
+	var err error
+	switch {
+	case moved && rotated:
+		err = c.SendPositionAndRotation(state.X, state.Y, state.Z, state.Yaw, state.Pitch, state.OnGround)
+	case moved:
+		err = c.SendPosition(state.X, state.Y, state.Z, state.OnGround)
+	case rotated:
+		err = c.SendRotation(state.Yaw, state.Pitch, state.OnGround)
+	case state.OnGround != p.lastOnGround:
+		err = c.SendOnGround(state.OnGround)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot send position: %w", err)
+	}
+
+	if moved {
+		p.lastX, p.lastY, p.lastZ = state.X, state.Y, state.Z
+		p.ticksSincePositionSent = 0
+	}
+	if rotated {
+		p.lastYaw, p.lastPitch = state.Yaw, state.Pitch
+	}
+	p.lastOnGround = state.OnGround
+	return nil
+}
+
+// movementSpeed returns the movement speed attribute without the sprinting
+// modifier, see sprintModifierUUID.
+func (c *Client) movementSpeed() float64 {
+	entity, ok := c.PlayerEntity()
+	if !ok {
+		return defaultMoveSpeed
+	}
+
+	attr, ok := entity.Attributes[AttrMovementSpeed]
+	if !ok {
+		return defaultMoveSpeed
+	}
+
+	modifiers := attr.Modifiers[:0:0]
+	for _, m := range attr.Modifiers {
+		if m.UUID != sprintModifierUUID {
+			modifiers = append(modifiers, m)
+		}
+	}
+	attr.Modifiers = modifiers
+	return attr.Value()
+}
+
+func (s PlayerState) sneaking() bool {
+	return s.Pose == proto.PoseSneaking
+}
+
+// eyeY returns the height of the player eyes.
+func (s PlayerState) eyeY() float64 {
+	if s.sneaking() {
+		return s.Y + playerSneakEyeHeight
+	}
+	return s.Y + playerEyeHeight
+}
+
+// sendLook sends the current rotation right away so that the server sees
+// the player facing what it interacts with.
+func (c *Client) sendLook() error {
+	p := c.physics
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := c.Player.State()
+	if err := c.SendRotation(state.Yaw, state.Pitch, state.OnGround); err != nil {
+		return err
+	}
+	p.lastYaw, p.lastPitch = state.Yaw, state.Pitch
+	return nil
+}
+
+// travel moves the player by one tick. Caller must hold p.mu.
+func (c *Client) travel(state *PlayerState) {
+	p := c.physics
+	ctrl := p.controls
+
+	forward, strafe := ctrl.Forward, ctrl.Strafe
+	if ctrl.Sneak {
+		forward *= sneakMultiplier
+		strafe *= sneakMultiplier
+	}
+	state.Pose = proto.PoseStanding
+	if ctrl.Sneak {
+		state.Pose = proto.PoseSneaking
+	}
+
+	speed := c.movementSpeed()
+	if p.sprinting {
+		speed *= sprintMultiplier
+	}
+
+	entity, _ := c.PlayerEntity()
+	box := playerBox(*state)
+	inWater := c.touchesFluid(box, FluidWater)
+	inLava := c.touchesFluid(box, FluidLava)
+
+	if ctrl.Jump {
+		if inWater || inLava {
+			p.velY += 0.04
+		} else if state.OnGround {
+			p.velY = jumpVelocity
+			if boost, ok := entity.Effect(EffectJumpBoost); ok {
+				p.velY += 0.1 * float64(boost.Amplifier+1)
+			}
+			if p.sprinting {
+				yaw := float64(state.Yaw) * math.Pi / 180
+				p.velX -= math.Sin(yaw) * sprintJumpBoost
+				p.velZ += math.Cos(yaw) * sprintJumpBoost
+			}
+		}
+	}
+
+	g := gravity
+	if _, ok := entity.Effect(EffectSlowFalling); ok && p.velY <= 0 {
+		g = 0.01
+	}
+
+	switch {
+	case inWater:
+		friction := 0.8
+		if p.sprinting {
+			// swimming follows the view direction vertically
+			friction = 0.9
+			look := -math.Sin(float64(state.Pitch) * math.Pi / 180)
+			rate := 0.06
+			if look < -0.2 {
+				rate = 0.085
+			}
+			if look <= 0 || ctrl.Jump {
+				p.velY += (look - p.velY) * rate
+			}
+		}
+		p.accelerate(forward, strafe, 0.02, state.Yaw)
+		c.move(state)
+		p.velX *= friction
+		p.velY *= 0.8
+		p.velZ *= friction
+		if !p.sprinting {
+			p.velY -= g / 16
+		}
+		if p.horizontalCollision && !c.collides(playerBox(*state).Offset(p.velX, p.velY+0.6, p.velZ)) {
+			p.velY = 0.3
+		}
+	case inLava:
+		p.accelerate(forward, strafe, 0.02, state.Yaw)
+		c.move(state)
+		p.velX *= 0.5
+		p.velY *= 0.5
+		p.velZ *= 0.5
+		p.velY -= g / 4
+	default:
+		slip := 1.0
+		if state.OnGround {
+			slip = defaultSlipperiness
+		}
+
+		accel := 0.02
+		if state.OnGround {
+			accel = speed * (0.21600002 / (slip * slip * slip))
+		} else if p.sprinting {
+			accel = 0.026
+		}
+		p.accelerate(forward, strafe, accel, state.Yaw)
+
+		climbing := c.block(feetPosition(*state)).Climbable
+		if climbing {
+			p.velX = math.Max(-0.15, math.Min(0.15, p.velX))
+			p.velZ = math.Max(-0.15, math.Min(0.15, p.velZ))
+			p.velY = math.Max(-0.15, p.velY)
+			if ctrl.Sneak && p.velY < 0 {
+				p.velY = 0
+			}
+		}
+		c.move(state)
+		if climbing && (p.horizontalCollision || ctrl.Jump) {
+			p.velY = 0.2
+		}
+
+		if levitation, ok := entity.Effect(EffectLevitation); ok {
+			p.velY += (0.05*float64(levitation.Amplifier+1) - p.velY) * 0.2
+		} else {
+			p.velY -= g
+		}
+		p.velY *= airDrag
+		p.velX *= slip * 0.91
+		p.velZ *= slip * 0.91
+	}
+}
+
+// accelerate adds the input rotated by yaw to the velocity.
+func (p *physics) accelerate(forward, strafe, accel float64, yaw float32) {
+	length := forward*forward + strafe*strafe
+	if length < 1e-7 {
+		return
+	}
+	if length = math.Sqrt(length); length < 1 {
+		length = 1
+	}
+	forward, strafe = forward*accel/length, strafe*accel/length
+
+	sin, cos := math.Sincos(float64(yaw) * math.Pi / 180)
+	p.velX += strafe*cos - forward*sin
+	p.velZ += forward*cos + strafe*sin
+}
+
+// feetPosition returns the block the player stands in.
+func feetPosition(state PlayerState) proto.Position {
+	return proto.Position{
+		X: int32(math.Floor(state.X)),
+		Y: int32(math.Floor(state.Y)),
+		Z: int32(math.Floor(state.Z)),
+	}
+}
+
+func playerBox(state PlayerState) AABB {
+	height := playerHeight
+	if state.sneaking() {
+		height = playerSneakHeight
+	}
+	return AABB{
+		MinX: state.X - playerWidth/2, MinY: state.Y, MinZ: state.Z - playerWidth/2,
+		MaxX: state.X + playerWidth/2, MaxY: state.Y + height, MaxZ: state.Z + playerWidth/2,
+	}
+}
+
+// blockBoxes returns collision boxes of all blocks intersecting area.
+func (c *Client) blockBoxes(area AABB) []AABB {
+	var boxes []AABB
+	blocks := c.Blocks
+	for x := int32(math.Floor(area.MinX)); x <= int32(math.Floor(area.MaxX)); x++ {
+		// fences and walls stick half a block above their position
+		for y := int32(math.Floor(area.MinY)) - 1; y <= int32(math.Floor(area.MaxY)); y++ {
+			for z := int32(math.Floor(area.MinZ)); z <= int32(math.Floor(area.MaxZ)); z++ {
+				pos := proto.Position{X: x, Y: y, Z: z}
+				for _, shape := range blocks.Block(c.World.BlockState(pos)).Shapes {
+					box := shape.Offset(float64(x), float64(y), float64(z))
+					if box.Intersects(area) {
+						boxes = append(boxes, box)
+					}
+				}
+			}
+		}
+	}
+	return boxes
+}
+
+func (c *Client) collides(box AABB) bool {
+	return len(c.blockBoxes(box)) > 0
+}
+
+func (c *Client) touchesFluid(box AABB, fluid Fluid) bool {
+	blocks := c.Blocks
+	for x := int32(math.Floor(box.MinX + 0.001)); x <= int32(math.Floor(box.MaxX-0.001)); x++ {
+		for y := int32(math.Floor(box.MinY + 0.001)); y <= int32(math.Floor(box.MaxY-0.001)); y++ {
+			for z := int32(math.Floor(box.MinZ + 0.001)); z <= int32(math.Floor(box.MaxZ-0.001)); z++ {
+				if blocks.Block(c.World.BlockState(proto.Position{X: x, Y: y, Z: z})).Fluid == fluid {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// move applies the velocity to the player position resolving collisions
+// with blocks. Caller must hold p.mu.
+func (c *Client) move(state *PlayerState) {
+	p := c.physics
+	dx, dy, dz := p.velX, p.velY, p.velZ
+	box := playerBox(*state)
+
+	if p.controls.Sneak && state.OnGround && dy <= 0 {
+		dx, dz = c.backOffFromEdge(box, dx, dz)
+	}
+
+	mx, my, mz := c.collide(box, dx, dy, dz)
+
+	// step up blocks like stairs and slabs when walking into them
+	onGround := state.OnGround || (dy != my && dy < 0)
+	if onGround && (mx != dx || mz != dz) {
+		sx, sy, sz := c.collide(box, dx, playerStepHeight, dz)
+		_, down, _ := c.collide(box.Offset(sx, sy, sz), 0, -sy, 0)
+		sy += down
+		if sx*sx+sz*sz > mx*mx+mz*mz {
+			mx, my, mz = sx, sy, sz
+		}
+	}
+
+	state.X += mx
+	state.Y += my
+	state.Z += mz
+
+	p.horizontalCollision = mx != dx || mz != dz
+	state.OnGround = my != dy && dy < 0
+	if mx != dx {
+		p.velX = 0
+	}
+	if my != dy {
+		p.velY = 0
+	}
+	if mz != dz {
+		p.velZ = 0
+	}
+}
+
+// collide returns how far box can move by given offset.
+func (c *Client) collide(box AABB, dx, dy, dz float64) (float64, float64, float64) {
+	area := box
+	area.MinX, area.MaxX = math.Min(box.MinX, box.MinX+dx), math.Max(box.MaxX, box.MaxX+dx)
+	area.MinY, area.MaxY = math.Min(box.MinY, box.MinY+dy), math.Max(box.MaxY, box.MaxY+dy)
+	area.MinZ, area.MaxZ = math.Min(box.MinZ, box.MinZ+dz), math.Max(box.MaxZ, box.MaxZ+dz)
+	boxes := c.blockBoxes(area)
+
+	for _, b := range boxes {
+		dy = clipY(b, box, dy)
+	}
+	box = box.Offset(0, dy, 0)
+
+	if math.Abs(dx) >= math.Abs(dz) {
+		for _, b := range boxes {
+			dx = clipX(b, box, dx)
+		}
+		box = box.Offset(dx, 0, 0)
+		for _, b := range boxes {
+			dz = clipZ(b, box, dz)
+		}
+	} else {
+		for _, b := range boxes {
+			dz = clipZ(b, box, dz)
+		}
+		box = box.Offset(0, 0, dz)
+		for _, b := range boxes {
+			dx = clipX(b, box, dx)
+		}
+	}
+	return dx, dy, dz
+}
+
+// backOffFromEdge reduces horizontal movement so that a sneaking player
+// does not walk off a block edge.
+func (c *Client) backOffFromEdge(box AABB, dx, dz float64) (float64, float64) {
+	const step = 0.05
+	unsupported := func(x, z float64) bool {
+		return !c.collides(box.Offset(x, -playerStepHeight, z))
+	}
+
+	for dx != 0 && unsupported(dx, 0) {
+		dx = towardsZero(dx, step)
+	}
+	for dz != 0 && unsupported(0, dz) {
+		dz = towardsZero(dz, step)
+	}
+	for dx != 0 && dz != 0 && unsupported(dx, dz) {
+		dx = towardsZero(dx, step)
+		dz = towardsZero(dz, step)
+	}
+	return dx, dz
+}
+
+func towardsZero(v, step float64) float64 {
+	switch {
+	case v < step && v >= -step:
+		return 0
+	case v > 0:
+		return v - step
+	default:
+		return v + step
+	}
+}
+
+func clipX(b, box AABB, dx float64) float64 {
+	if box.MaxY <= b.MinY || box.MinY >= b.MaxY || box.MaxZ <= b.MinZ || box.MinZ >= b.MaxZ {
+		return dx
+	}
+	if dx > 0 && box.MaxX <= b.MinX {
+		return math.Min(dx, b.MinX-box.MaxX)
+	}
+	if dx < 0 && box.MinX >= b.MaxX {
+		return math.Max(dx, b.MaxX-box.MinX)
+	}
+	return dx
+}
+
+func clipY(b, box AABB, dy float64) float64 {
+	if box.MaxX <= b.MinX || box.MinX >= b.MaxX || box.MaxZ <= b.MinZ || box.MinZ >= b.MaxZ {
+		return dy
+	}
+	if dy > 0 && box.MaxY <= b.MinY {
+		return math.Min(dy, b.MinY-box.MaxY)
+	}
+	if dy < 0 && box.MinY >= b.MaxY {
+		return math.Max(dy, b.MaxY-box.MinY)
+	}
+	return dy
+}
+
+func clipZ(b, box AABB, dz float64) float64 {
+	if box.MaxX <= b.MinX || box.MinX >= b.MaxX || box.MaxY <= b.MinY || box.MinY >= b.MaxY {
+		return dz
+	}
+	if dz > 0 && box.MaxZ <= b.MinZ {
+		return math.Min(dz, b.MinZ-box.MaxZ)
+	}
+	if dz < 0 && box.MinZ >= b.MaxZ {
+		return math.Max(dz, b.MaxZ-box.MinZ)
+	}
+	return dz
+}
@@ -1,6 +1,153 @@
 package mc
 
+import (
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+const (
+	GameModeSurvival  = 0
+	GameModeCreative  = 1
+	GameModeAdventure = 2
+	GameModeSpectator = 3
+)
+
+// Bits of Abilities.Flags.
+const (
+	AbilityInvulnerable = 0x01
+	AbilityFlying       = 0x02
+	AbilityAllowFlying  = 0x04
+	AbilityCreativeMode = 0x08
+)
+
+type Abilities struct {
+	Flags       byte
+	FlyingSpeed float32
+	FOVModifier float32
+}
+
+func (a Abilities) Flying() bool {
+	return a.Flags&AbilityFlying != 0
+}
+
+// PlayerState is a snapshot of everything the server told about the player.
+type PlayerState struct {
+	EntityID      int32
+	GameMode      byte
+	Hardcore      bool
+	Dimension     string
+	DimensionType string
+
+	X, Y, Z  float64
+	Yaw      float32
+	Pitch    float32
+	OnGround bool
+	Pose     proto.Pose
+
+	Abilities Abilities
+
+	Health     float32
+	Food       int32
+	Saturation float32
+
+	ExperienceBar   float32
+	Level           int32
+	TotalExperience int32
+
+	HeldSlot int8 // HeldSlot is the selected hotbar slot, 0-8
+}
+
 type Player struct {
-	Name string
-	UUID string
+	mu    sync.RWMutex
+	name  string
+	uuid  string
+	state PlayerState
+}
+
+// Name returns the player name, as confirmed by the server after login.
+func (p *Player) Name() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.name
+}
+
+// UUID returns the player UUID, as assigned by the server after login.
+func (p *Player) UUID() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.uuid
+}
+
+func (p *Player) setProfile(name, uuid string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.name, p.uuid = name, uuid
+}
+
+// State returns a snapshot of the player state.
+func (p *Player) State() PlayerState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state
+}
+
+func (p *Player) EntityID() int32 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.EntityID
+}
+
+func (p *Player) GameMode() byte {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.GameMode
+}
+
+func (p *Player) Dimension() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.Dimension
+}
+
+func (p *Player) Position() (x, y, z float64) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.X, p.state.Y, p.state.Z
+}
+
+func (p *Player) Rotation() (yaw, pitch float32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.Yaw, p.state.Pitch
+}
+
+func (p *Player) Abilities() Abilities {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.Abilities
+}
+
+func (p *Player) Health() (health float32, food int32, saturation float32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.Health, p.state.Food, p.state.Saturation
+}
+
+func (p *Player) Experience() (bar float32, level, total int32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.ExperienceBar, p.state.Level, p.state.TotalExperience
+}
+
+func (p *Player) HeldSlot() int8 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state.HeldSlot
+}
+
+// update calls fn with the state while holding the lock.
+func (p *Player) update(fn func(state *PlayerState)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(&p.state)
 }
@@ -0,0 +1,170 @@
+package mc
+
+import (
+	"log"
+	"mc-bot/mc/proto"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlayerListEntry is a player known to the client from the tab list. The
+// player does not have to be near, see Client.PlayerByName.
+type PlayerListEntry struct {
+	UUID        proto.Uuid
+	Name        string
+	Properties  []proto.ProfileProperty // Properties holds e.g. the signed skin "textures"
+	GameMode    int32
+	Listed      bool  // Listed is false for players hidden from the tab list
+	Latency     int32 // Latency is in milliseconds
+	DisplayName proto.Chat
+}
+
+// PlayerList tracks the players on the server. It is safe for concurrent
+// use.
+type PlayerList struct {
+	mu      sync.RWMutex
+	players map[proto.Uuid]*PlayerListEntry
+}
+
+func newPlayerList() *PlayerList {
+	return &PlayerList{players: map[proto.Uuid]*PlayerListEntry{}}
+}
+
+func (l *PlayerList) Get(uuid proto.Uuid) (PlayerListEntry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	player, ok := l.players[uuid]
+	if !ok {
+		return PlayerListEntry{}, false
+	}
+	return *player, true
+}
+
+// ByName returns the player with name, ignoring case like the server does.
+func (l *PlayerList) ByName(name string) (PlayerListEntry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, player := range l.players {
+		if strings.EqualFold(player.Name, name) {
+			return *player, true
+		}
+	}
+	return PlayerListEntry{}, false
+}
+
+// All returns all players sorted by name.
+func (l *PlayerList) All() []PlayerListEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	out := make([]PlayerListEntry, 0, len(l.players))
+	for _, player := range l.players {
+		out = append(out, *player)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// update applies info to the player and returns the result and whether the
+// player was added.
+func (l *PlayerList) update(actions proto.Byte, info proto.PlayerInfo) (PlayerListEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	player, ok := l.players[info.UUID]
+	added := actions&proto.PlayerInfoAddPlayer != 0
+	if !ok {
+		if !added {
+			// updates of unknown players are ignored like in vanilla
+			return PlayerListEntry{}, false
+		}
+		player = &PlayerListEntry{UUID: info.UUID}
+		l.players[info.UUID] = player
+	}
+
+	if added {
+		player.Name = string(info.Name)
+		player.Properties = info.Properties
+	}
+	if actions&proto.PlayerInfoUpdateGameMode != 0 {
+		player.GameMode = int32(info.GameMode)
+	}
+	if actions&proto.PlayerInfoUpdateListed != 0 {
+		player.Listed = bool(info.Listed)
+	}
+	if actions&proto.PlayerInfoUpdateLatency != 0 {
+		player.Latency = int32(info.Latency)
+	}
+	if actions&proto.PlayerInfoUpdateDisplayName != 0 {
+		player.DisplayName = info.DisplayName.Value
+	}
+	return *player, added && !ok
+}
+
+func (l *PlayerList) remove(uuid proto.Uuid) (PlayerListEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	player, ok := l.players[uuid]
+	if !ok {
+		return PlayerListEntry{}, false
+	}
+	delete(l.players, uuid)
+	return *player, true
+}
+
+// PlayerByName returns the entity of a player by name. It is only found
+// while the player is within view distance.
+func (c *Client) PlayerByName(name string) (Entity, bool) {
+	player, ok := c.Players.ByName(name)
+	if !ok {
+		return Entity{}, false
+	}
+	return c.Entities.ByUUID(player.UUID)
+}
+
+func (c *Client) handlePlayerInfoUpdate(pk proto.Packet) error {
+	var update proto.PlayerInfoUpdateResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	for _, info := range update.Players {
+		if update.Actions&proto.PlayerInfoInitializeChat != 0 {
+			if !info.ChatSession.Present {
+				c.sessions.remove(info.UUID)
+			} else if err := c.sessions.set(info.UUID, info.ChatSession.Value); err != nil {
+				// messages of the player stay unverified, the rest of the
+				// update is still applied
+				log.Printf("[WARN] %v", err)
+				c.sessions.remove(info.UUID)
+			}
+		}
+
+		player, joined := c.Players.update(update.Actions, info)
+		if joined {
+			c.emit(PlayerJoinedEvent{Player: player})
+		} else if player.Name != "" {
+			c.emit(PlayerUpdatedEvent{Player: player, Actions: byte(update.Actions)})
+		}
+	}
+	return nil
+}
+
+func (c *Client) handlePlayerInfoRemove(pk proto.Packet) error {
+	var removed proto.PlayerInfoRemoveResponse
+	if err := pk.Scan(&removed); err != nil {
+		return err
+	}
+
+	for _, uuid := range removed.Players {
+		c.sessions.remove(uuid)
+		if player, ok := c.Players.remove(uuid); ok {
+			c.emit(PlayerLeftEvent{Player: player})
+		}
+	}
+	return nil
+}
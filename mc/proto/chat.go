@@ -0,0 +1,120 @@
+package proto
+
+import "io"
+
+// SignatureSize is the size of chat message signatures.
+const SignatureSize = 256
+
+// Signature is a chat message signature.
+type Signature [SignatureSize]byte
+
+func (s *Signature) WriteTo(w io.Writer) (int64, error) {
+	return int64Wrap(w.Write(s[:]))
+}
+
+func (s *Signature) ReadFrom(r io.Reader) (int64, error) {
+	return readFull(r, s[:])
+}
+
+// LastSeenMessages is the number of messages a client acknowledges at most.
+const LastSeenMessages = 20
+
+// LastSeenBits is a Fixed BitSet of LastSeenMessages bits marking which of
+// the last seen messages are acknowledged. Bit i is bit i%8 of byte i/8.
+type LastSeenBits [(LastSeenMessages + 7) / 8]byte
+
+func (b *LastSeenBits) Set(i int) {
+	b[i/8] |= 1 << (i % 8)
+}
+
+func (b *LastSeenBits) Get(i int) bool {
+	return b[i/8]&(1<<(i%8)) != 0
+}
+
+func (b *LastSeenBits) WriteTo(w io.Writer) (int64, error) {
+	return int64Wrap(w.Write(b[:]))
+}
+
+func (b *LastSeenBits) ReadFrom(r io.Reader) (int64, error) {
+	return readFull(r, b[:])
+}
+
+// PreviousMessage references a message the sender had seen. ID is the
+// message index plus one, or 0 when Signature is sent in full.
+type PreviousMessage struct {
+	ID        VarInt
+	Signature Signature
+}
+
+func (m *PreviousMessage) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := m.ID.ReadFrom(r)
+	if err != nil || m.ID != 0 {
+		return nn, err
+	}
+
+	n, err := m.Signature.ReadFrom(r)
+	return nn + n, err
+}
+
+func (m *PreviousMessage) WriteTo(w io.Writer) (int64, error) {
+	if m.ID != 0 {
+		return m.ID.WriteTo(w)
+	}
+	return writeAll(w, &m.ID, &m.Signature)
+}
+
+// Filter types of Player Chat.
+const (
+	FilterPassThrough    = VarInt(0)
+	FilterFullyFiltered  = VarInt(1)
+	FilterPartlyFiltered = VarInt(2)
+)
+
+// PlayerChatResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Chat_Message
+type PlayerChatResponse struct {
+	Sender           Uuid
+	Index            VarInt
+	Signature        Optional[Signature]
+	Message          String
+	Timestamp        Long // Timestamp is in milliseconds since the epoch
+	Salt             Long
+	PreviousMessages Array[PreviousMessage]
+	UnsignedContent  Optional[Chat]
+	FilterType       VarInt
+	FilterBits       Array[Long] // FilterBits is only sent with FilterPartlyFiltered
+	ChatType         VarInt
+	SenderName       Chat
+	TargetName       Optional[Chat]
+}
+
+func (p *PlayerChatResponse) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := readAll(r, &p.Sender, &p.Index, &p.Signature, &p.Message, &p.Timestamp, &p.Salt,
+		&p.PreviousMessages, &p.UnsignedContent, &p.FilterType)
+	if err != nil {
+		return nn, err
+	}
+
+	var n int64
+	if p.FilterType == FilterPartlyFiltered {
+		n, err = p.FilterBits.ReadFrom(r)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+	}
+
+	n, err = readAll(r, &p.ChatType, &p.SenderName, &p.TargetName)
+	return nn + n, err
+}
+
+type SystemChatResponse struct {
+	Content Chat
+	Overlay Bool // Overlay messages are shown above the hotbar
+}
+
+type DisguisedChatResponse struct {
+	Message    Chat
+	ChatType   VarInt
+	SenderName Chat
+	TargetName Optional[Chat]
+}
@@ -0,0 +1,46 @@
+package proto
+
+import "testing"
+
+func TestReadPlayerChat(t *testing.T) {
+	var sig Signature
+	sig[0], sig[255] = 0xaa, 0xbb
+
+	values := []any{
+		&Uuid{1, 2}, NewVarInt(3),
+		NewBool(true), &sig,
+		NewString("hi"), NewLong(1000), NewLong(42),
+		NewVarInt(2), NewVarInt(5), NewVarInt(0), &sig, // previous messages
+		NewBool(false), // unsigned content
+		NewVarInt(int(FilterPartlyFiltered)), &Array[Long]{7},
+		NewVarInt(0), NewString(`{"text":"Bob"}`), NewBool(false),
+	}
+
+	want := PlayerChatResponse{
+		Sender:           Uuid{1, 2},
+		Index:            3,
+		Signature:        Optional[Signature]{Present: true, Value: sig},
+		Message:          "hi",
+		Timestamp:        1000,
+		Salt:             42,
+		PreviousMessages: Array[PreviousMessage]{{ID: 5}, {ID: 0, Signature: sig}},
+		FilterType:       FilterPartlyFiltered,
+		FilterBits:       Array[Long]{7},
+		SenderName:       `{"text":"Bob"}`,
+	}
+	testScan(t, values, &PlayerChatResponse{}, &want)
+}
+
+func TestLastSeenBits(t *testing.T) {
+	var bits LastSeenBits
+	bits.Set(0)
+	bits.Set(9)
+	bits.Set(19)
+
+	if want := (LastSeenBits{0x01, 0x02, 0x08}); bits != want {
+		t.Errorf("Want: %v, Got: %v", want, bits)
+	}
+	if !bits.Get(9) || bits.Get(10) {
+		t.Errorf("unexpected bits %v", bits)
+	}
+}
@@ -0,0 +1,295 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// ByteArray is an array of bytes prefixed with its length as VarInt.
+type ByteArray []byte
+
+func (b *ByteArray) WriteTo(w io.Writer) (int64, error) {
+	l := VarInt(len(*b))
+	nn, err := l.WriteTo(w)
+	if err != nil {
+		return nn, err
+	}
+
+	n, err := int64Wrap(w.Write(*b))
+	return nn + n, err
+}
+
+func (b *ByteArray) ReadFrom(r io.Reader) (int64, error) {
+	l := VarInt(0)
+	nn, err := l.ReadFrom(r)
+	if err != nil {
+		return nn, err
+	}
+	if l < 0 {
+		return nn, fmt.Errorf("negative byte array length: %d", l)
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r, int64(l)))
+	nn += int64(len(buf))
+	if err == nil && len(buf) != int(l) {
+		err = io.ErrUnexpectedEOF
+	}
+	*b = buf
+	return nn, err
+}
+
+// https://wiki.vg/index.php?title=Chunk_Format&oldid=18375#Paletted_Container_structure
+const (
+	SectionBlocks = 16 * 16 * 16
+	SectionBiomes = 4 * 4 * 4
+
+	maxIndirectBlockBits = 8
+	maxIndirectBiomeBits = 3
+	minIndirectBlockBits = 4
+)
+
+// PalettedContainer stores Size values packed into longs. Depending on Bits
+// it is single valued (Bits is 0, Palette has one entry), indirect (values
+// are indexes to Palette) or direct (Palette is nil, values are stored as is).
+type PalettedContainer struct {
+	Size        int
+	Bits        int
+	MaxIndirect int
+	Palette     []int32
+	Data        []uint64
+}
+
+func NewPalettedContainer(size, maxIndirect int, value int32) *PalettedContainer {
+	return &PalettedContainer{Size: size, MaxIndirect: maxIndirect, Palette: []int32{value}}
+}
+
+func (p *PalettedContainer) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	var bitsPerEntry UByte
+	if _, err := bitsPerEntry.ReadFrom(cr); err != nil {
+		return cr.n, err
+	}
+	p.Bits, p.Palette, p.Data = int(bitsPerEntry), nil, nil
+
+	if p.Bits <= p.MaxIndirect {
+		var palette Array[VarInt]
+		if p.Bits == 0 {
+			palette = Array[VarInt]{0}
+			if _, err := palette[0].ReadFrom(cr); err != nil {
+				return cr.n, err
+			}
+		} else if _, err := palette.ReadFrom(cr); err != nil {
+			return cr.n, err
+		}
+
+		p.Palette = make([]int32, len(palette))
+		for i, v := range palette {
+			p.Palette[i] = int32(v)
+		}
+	}
+
+	var data Array[Long]
+	if _, err := data.ReadFrom(cr); err != nil {
+		return cr.n, err
+	}
+	p.Data = make([]uint64, len(data))
+	for i, v := range data {
+		p.Data[i] = uint64(v)
+	}
+
+	if p.Bits > 0 && len(p.Data) < p.longs(p.Bits) {
+		return cr.n, fmt.Errorf("paletted container has %d longs, want %d", len(p.Data), p.longs(p.Bits))
+	}
+	return cr.n, nil
+}
+
+func (p *PalettedContainer) perLong(bits int) int {
+	return 64 / bits
+}
+
+func (p *PalettedContainer) longs(bits int) int {
+	per := p.perLong(bits)
+	return (p.Size + per - 1) / per
+}
+
+func (p *PalettedContainer) raw(i int) uint64 {
+	per := p.perLong(p.Bits)
+	shift := (i % per) * p.Bits
+	return (p.Data[i/per] >> shift) & (1<<p.Bits - 1)
+}
+
+func (p *PalettedContainer) setRaw(i int, v uint64) {
+	per := p.perLong(p.Bits)
+	shift := (i % per) * p.Bits
+	mask := uint64(1<<p.Bits-1) << shift
+	p.Data[i/per] = p.Data[i/per]&^mask | v<<shift
+}
+
+// Get returns the value at index i.
+func (p *PalettedContainer) Get(i int) int32 {
+	if p.Bits == 0 {
+		return p.Palette[0]
+	}
+
+	v := p.raw(i)
+	if p.Palette == nil {
+		return int32(v)
+	}
+	if int(v) >= len(p.Palette) {
+		return 0
+	}
+	return p.Palette[v]
+}
+
+// Set stores value at index i, growing the palette when needed.
+func (p *PalettedContainer) Set(i int, value int32) {
+	if p.Palette == nil {
+		if bits.Len32(uint32(value)) > p.Bits {
+			p.grow(value)
+		}
+		p.setRaw(i, uint64(value))
+		return
+	}
+
+	for idx, v := range p.Palette {
+		if v == value {
+			if p.Bits == 0 {
+				return
+			}
+			p.setRaw(i, uint64(idx))
+			return
+		}
+	}
+
+	p.grow(value)
+	p.Set(i, value)
+}
+
+// grow repacks the container so that it can also hold value, switching to
+// direct storage once the palette would need more than MaxIndirect bits.
+func (p *PalettedContainer) grow(value int32) {
+	values := make([]int32, p.Size)
+	for i := range values {
+		values[i] = p.Get(i)
+	}
+
+	newBits := p.MaxIndirect + 1
+	if p.Palette != nil {
+		newBits = bits.Len(uint(len(p.Palette)))
+		if newBits < minIndirectBlockBits && p.MaxIndirect == maxIndirectBlockBits {
+			newBits = minIndirectBlockBits
+		}
+	}
+
+	if newBits > p.MaxIndirect {
+		maxValue := value
+		for _, v := range values {
+			if v > maxValue {
+				maxValue = v
+			}
+		}
+		p.Palette = nil
+		p.Bits = bits.Len32(uint32(maxValue))
+		if p.Bits < newBits {
+			p.Bits = newBits
+		}
+	} else {
+		p.Palette = append(p.Palette, value)
+		p.Bits = newBits
+	}
+
+	p.Data = make([]uint64, p.longs(p.Bits))
+	for i, v := range values {
+		if p.Palette == nil {
+			p.setRaw(i, uint64(v))
+			continue
+		}
+		for idx, pv := range p.Palette {
+			if pv == v {
+				p.setRaw(i, uint64(idx))
+				break
+			}
+		}
+	}
+}
+
+// ChunkSection https://wiki.vg/index.php?title=Chunk_Format&oldid=18375#Chunk_Section_structure
+type ChunkSection struct {
+	BlockCount  Short
+	BlockStates *PalettedContainer
+	Biomes      *PalettedContainer
+}
+
+func (s *ChunkSection) ReadFrom(r io.Reader) (int64, error) {
+	s.BlockStates = &PalettedContainer{Size: SectionBlocks, MaxIndirect: maxIndirectBlockBits}
+	s.Biomes = &PalettedContainer{Size: SectionBiomes, MaxIndirect: maxIndirectBiomeBits}
+	return readAll(r, &s.BlockCount, s.BlockStates, s.Biomes)
+}
+
+type ChunkBlockEntity struct {
+	PackedXZ UByte // PackedXZ is ((blockX & 15) << 4) | (blockZ & 15)
+	Y        Short
+	Type     VarInt
+	Data     NBT
+}
+
+// ChunkDataResponse holds the chunk part of Chunk Data and Update Light, the
+// light data that follows is not decoded.
+type ChunkDataResponse struct {
+	ChunkX        Int
+	ChunkZ        Int
+	Heightmaps    NBT
+	Data          ByteArray // Data holds ChunkSection for every section of the dimension
+	BlockEntities Array[ChunkBlockEntity]
+}
+
+type UnloadChunkResponse struct {
+	ChunkX Int
+	ChunkZ Int
+}
+
+type BlockEntityDataResponse struct {
+	Location Position
+	Type     VarInt
+	Data     NBT // Data is a TAG_End when the block entity is removed
+}
+
+type OpenSignEditorResponse struct {
+	Location    Position
+	IsFrontText Bool
+}
+
+type BlockUpdateResponse struct {
+	Location Position
+	BlockID  VarInt
+}
+
+// SectionPosition is the position of a chunk section packed into a Long:
+// x as 22 bits, z as 22 bits and y as 20 bits.
+type SectionPosition struct {
+	X, Y, Z int32
+}
+
+func (p *SectionPosition) ReadFrom(r io.Reader) (int64, error) {
+	var val Long
+	n, err := val.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	p.X = int32(val >> 42)
+	p.Y = int32(val << 44 >> 44)
+	p.Z = int32(val << 22 >> 42)
+	return n, nil
+}
+
+func (p *SectionPosition) WriteTo(w io.Writer) (int64, error) {
+	val := Long((int64(p.X)&0x3FFFFF)<<42 | (int64(p.Z)&0x3FFFFF)<<20 | int64(p.Y)&0xFFFFF)
+	return val.WriteTo(w)
+}
+
+type UpdateSectionBlocksResponse struct {
+	Section SectionPosition
+	Blocks  Array[VarLong] // Blocks are packed as blockState << 12 | (x << 8 | z << 4 | y)
+}
@@ -0,0 +1,57 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPalettedContainerSet(t *testing.T) {
+	p := NewPalettedContainer(SectionBlocks, maxIndirectBlockBits, 0)
+	want := make([]int32, SectionBlocks)
+	for i := range want {
+		// enough distinct values to go from single valued through indirect
+		// to direct storage
+		want[i] = int32(i % 300 * 37)
+		p.Set(i, want[i])
+	}
+
+	if p.Palette != nil {
+		t.Errorf("Want direct storage, Got palette of %d entries", len(p.Palette))
+	}
+	for i, v := range want {
+		if got := p.Get(i); got != v {
+			t.Fatalf("index %d: Want: %d, Got: %d", i, v, got)
+		}
+	}
+}
+
+func TestReadChunkSection(t *testing.T) {
+	input := []byte{
+		0x00, 0x02, // block count
+		0x04, 0x02, 0x00, 0x01, // 4 bits per entry, palette [air, stone]
+		0x80, 0x02, // 256 longs
+	}
+	data := make([]byte, 256*8)
+	data[7] = 0x10  // index 1 is stone
+	data[15] = 0x01 // index 16 is stone
+	input = append(input, data...)
+	input = append(input, 0x00, 0x27, 0x00) // biomes: single valued 39, no data
+
+	var section ChunkSection
+	if _, err := section.ReadFrom(bytes.NewBuffer(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < SectionBlocks; i++ {
+		want := int32(0)
+		if i == 1 || i == 16 {
+			want = 1
+		}
+		if got := section.BlockStates.Get(i); got != want {
+			t.Fatalf("index %d: Want: %d, Got: %d", i, want, got)
+		}
+	}
+	if got := section.Biomes.Get(5); got != 39 {
+		t.Errorf("Want biome 39, Got: %d", got)
+	}
+}
@@ -0,0 +1,243 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+)
+
+// Bits of CommandNode.Flags, see
+// https://wiki.vg/index.php?title=Command_Data&oldid=18375
+const (
+	NodeTypeMask       = 0x03
+	NodeRoot           = 0x00
+	NodeLiteral        = 0x01
+	NodeArgument       = 0x02
+	NodeExecutable     = 0x04
+	NodeHasRedirect    = 0x08
+	NodeHasSuggestions = 0x10
+)
+
+// Parsers is the minecraft:command_argument_type registry indexed by
+// parser ID.
+var Parsers = []string{
+	"brigadier:bool",
+	"brigadier:float",
+	"brigadier:double",
+	"brigadier:integer",
+	"brigadier:long",
+	"brigadier:string",
+	"minecraft:entity",
+	"minecraft:game_profile",
+	"minecraft:block_pos",
+	"minecraft:column_pos",
+	"minecraft:vec3",
+	"minecraft:vec2",
+	"minecraft:block_state",
+	"minecraft:block_predicate",
+	"minecraft:item_stack",
+	"minecraft:item_predicate",
+	"minecraft:color",
+	"minecraft:component",
+	"minecraft:message",
+	"minecraft:nbt_compound_tag",
+	"minecraft:nbt_tag",
+	"minecraft:nbt_path",
+	"minecraft:objective",
+	"minecraft:objective_criteria",
+	"minecraft:operation",
+	"minecraft:particle",
+	"minecraft:angle",
+	"minecraft:rotation",
+	"minecraft:scoreboard_slot",
+	"minecraft:score_holder",
+	"minecraft:swizzle",
+	"minecraft:team",
+	"minecraft:item_slot",
+	"minecraft:resource_location",
+	"minecraft:function",
+	"minecraft:entity_anchor",
+	"minecraft:int_range",
+	"minecraft:float_range",
+	"minecraft:dimension",
+	"minecraft:gamemode",
+	"minecraft:time",
+	"minecraft:resource_or_tag",
+	"minecraft:resource_or_tag_key",
+	"minecraft:resource",
+	"minecraft:resource_key",
+	"minecraft:template_mirror",
+	"minecraft:template_rotation",
+	"minecraft:heightmap",
+	"minecraft:uuid",
+}
+
+// Bits of NumberProperties.Flags.
+const (
+	NumberHasMin = 0x01
+	NumberHasMax = 0x02
+)
+
+// NumberProperties limit the brigadier number parsers.
+type NumberProperties[T any] struct {
+	Flags    Byte
+	Min, Max T // Min and Max are only sent when the matching flag is set
+}
+
+func (p *NumberProperties[T]) ReadFrom(r io.Reader) (int64, error) {
+	var zero T
+	p.Min, p.Max = zero, zero
+	cr := &countingReader{r: r}
+	if _, err := p.Flags.ReadFrom(cr); err != nil {
+		return cr.n, err
+	}
+
+	if p.Flags&NumberHasMin != 0 {
+		if err := scanValue(cr, &p.Min); err != nil {
+			return cr.n, err
+		}
+	}
+	if p.Flags&NumberHasMax != 0 {
+		if err := scanValue(cr, &p.Max); err != nil {
+			return cr.n, err
+		}
+	}
+	return cr.n, nil
+}
+
+// Behaviors of brigadier:string.
+const (
+	StringSingleWord     = VarInt(0)
+	StringQuotablePhrase = VarInt(1)
+	StringGreedyPhrase   = VarInt(2)
+)
+
+// Bits of the flags of minecraft:entity.
+const (
+	EntitySingle      = 0x01
+	EntityPlayersOnly = 0x02
+)
+
+// CommandNode is a node of the command graph. Properties depend on Parser:
+//
+//	brigadier:float    NumberProperties[Float]
+//	brigadier:double   NumberProperties[Double]
+//	brigadier:integer  NumberProperties[Int]
+//	brigadier:long     NumberProperties[Long]
+//	brigadier:string   VarInt (one of String* behaviors)
+//	minecraft:entity   Byte (Entity* flags)
+//	minecraft:score_holder  Byte (0x01 allows multiple)
+//	minecraft:time     Int (minimum)
+//	minecraft:resource*     String (registry)
+//
+// and are nil for other parsers.
+type CommandNode struct {
+	Flags       Byte
+	Children    Array[VarInt]
+	Redirect    VarInt // Redirect is only sent with NodeHasRedirect
+	Name        String // Name is only sent for literal and argument nodes
+	ParserID    VarInt // ParserID and Properties are only sent for argument nodes
+	Properties  any
+	Suggestions String // Suggestions is only sent with NodeHasSuggestions
+}
+
+// Parser returns the name of the argument parser.
+func (n *CommandNode) Parser() string {
+	if n.ParserID < 0 || int(n.ParserID) >= len(Parsers) {
+		return fmt.Sprintf("unknown:%d", n.ParserID)
+	}
+	return Parsers[n.ParserID]
+}
+
+func (n *CommandNode) ReadFrom(r io.Reader) (int64, error) {
+	*n = CommandNode{}
+	cr := &countingReader{r: r}
+	if _, err := readAll(cr, &n.Flags, &n.Children); err != nil {
+		return cr.n, err
+	}
+	if n.Flags&NodeHasRedirect != 0 {
+		if _, err := n.Redirect.ReadFrom(cr); err != nil {
+			return cr.n, err
+		}
+	}
+
+	switch n.Flags & NodeTypeMask {
+	case NodeLiteral:
+		if _, err := n.Name.ReadFrom(cr); err != nil {
+			return cr.n, err
+		}
+	case NodeArgument:
+		if _, err := readAll(cr, &n.Name, &n.ParserID); err != nil {
+			return cr.n, err
+		}
+		if err := n.readProperties(cr); err != nil {
+			return cr.n, err
+		}
+	}
+
+	if n.Flags&NodeHasSuggestions != 0 {
+		if _, err := n.Suggestions.ReadFrom(cr); err != nil {
+			return cr.n, err
+		}
+	}
+	return cr.n, nil
+}
+
+func (n *CommandNode) readProperties(r io.Reader) error {
+	var err error
+	switch n.Parser() {
+	case "brigadier:float":
+		var v NumberProperties[Float]
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "brigadier:double":
+		var v NumberProperties[Double]
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "brigadier:integer":
+		var v NumberProperties[Int]
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "brigadier:long":
+		var v NumberProperties[Long]
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "brigadier:string":
+		var v VarInt
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "minecraft:entity", "minecraft:score_holder":
+		var v Byte
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "minecraft:time":
+		var v Int
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	case "minecraft:resource_or_tag", "minecraft:resource_or_tag_key", "minecraft:resource", "minecraft:resource_key":
+		var v String
+		_, err = v.ReadFrom(r)
+		n.Properties = v
+	default:
+		if n.ParserID < 0 || int(n.ParserID) >= len(Parsers) {
+			return fmt.Errorf("unknown command argument parser: %d", n.ParserID)
+		}
+	}
+	return err
+}
+
+type CommandsResponse struct {
+	Nodes Array[CommandNode]
+	Root  VarInt
+}
+
+type CommandSuggestion struct {
+	Match   String
+	Tooltip Optional[Chat]
+}
+
+type CommandSuggestionsResponse struct {
+	ID          VarInt
+	Start       VarInt
+	Length      VarInt
+	Suggestions Array[CommandSuggestion]
+}
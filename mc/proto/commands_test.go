@@ -0,0 +1,37 @@
+package proto
+
+import "testing"
+
+func TestReadCommands(t *testing.T) {
+	values := []any{
+		NewVarInt(4),
+		// root with child 1
+		NewByte(NodeRoot), &Array[VarInt]{1},
+		// literal "give" with child 2
+		NewByte(NodeLiteral), &Array[VarInt]{2}, NewString("give"),
+		// integer argument between 1 and 64 with server suggestions
+		NewByte(NodeArgument | NodeExecutable | NodeHasSuggestions), &Array[VarInt]{}, NewString("count"),
+		NewVarInt(3), NewByte(NumberHasMin | NumberHasMax), NewInt(1), NewInt(64), NewString("minecraft:ask_server"),
+		// literal "run" redirecting to the root
+		NewByte(NodeLiteral | NodeHasRedirect), &Array[VarInt]{}, NewVarInt(0), NewString("run"),
+		NewVarInt(0),
+	}
+
+	var got CommandsResponse
+	want := CommandsResponse{Nodes: Array[CommandNode]{
+		{Flags: NodeRoot, Children: Array[VarInt]{1}},
+		{Flags: NodeLiteral, Children: Array[VarInt]{2}, Name: "give"},
+		{
+			Flags:       NodeArgument | NodeExecutable | NodeHasSuggestions,
+			Name:        "count",
+			ParserID:    3,
+			Properties:  NumberProperties[Int]{Flags: NumberHasMin | NumberHasMax, Min: 1, Max: 64},
+			Suggestions: "minecraft:ask_server",
+		},
+		{Flags: NodeLiteral | NodeHasRedirect, Name: "run"},
+	}}
+	testScan(t, values, &got, &want)
+	if parser := got.Nodes[2].Parser(); parser != "brigadier:integer" {
+		t.Errorf("Want: brigadier:integer, Got: %s", parser)
+	}
+}
@@ -0,0 +1,144 @@
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Component is a decoded text component, see
+// https://wiki.vg/index.php?title=Text_formatting&oldid=18375
+type Component struct {
+	Text      string      `json:"text,omitempty"`
+	Translate string      `json:"translate,omitempty"`
+	With      []Component `json:"with,omitempty"`
+	Keybind   string      `json:"keybind,omitempty"`
+	Selector  string      `json:"selector,omitempty"`
+	Score     *Score      `json:"score,omitempty"`
+
+	Color         string `json:"color,omitempty"` // Color is a color name like "dark_red" or "#rrggbb"
+	Font          string `json:"font,omitempty"`
+	Bold          *bool  `json:"bold,omitempty"` // Bold and other styles are nil when inherited
+	Italic        *bool  `json:"italic,omitempty"`
+	Underlined    *bool  `json:"underlined,omitempty"`
+	Strikethrough *bool  `json:"strikethrough,omitempty"`
+	Obfuscated    *bool  `json:"obfuscated,omitempty"`
+
+	Insertion  string      `json:"insertion,omitempty"`
+	ClickEvent *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent *HoverEvent `json:"hoverEvent,omitempty"`
+
+	Extra []Component `json:"extra,omitempty"`
+}
+
+// Score is the content of a score component.
+type Score struct {
+	Name      string `json:"name"`
+	Objective string `json:"objective"`
+	Value     string `json:"value,omitempty"`
+}
+
+type ClickEvent struct {
+	Action string `json:"action"` // Action is e.g. "open_url" or "run_command"
+	Value  string `json:"value"`
+}
+
+type HoverEvent struct {
+	Action   string          `json:"action"` // Action is e.g. "show_text" or "show_item"
+	Contents json.RawMessage `json:"contents,omitempty"`
+}
+
+// UnmarshalJSON accepts the shorthand forms too: a string is a text
+// component and an array is its first element with the rest as extra.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case string:
+		*c = Component{Text: v}
+		return nil
+	case bool, float64:
+		// translation arguments may be plain values
+		*c = Component{Text: fmt.Sprint(v)}
+		return nil
+	case []any:
+		var parts []Component
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return err
+		}
+		if len(parts) == 0 {
+			return fmt.Errorf("empty text component array")
+		}
+		*c = parts[0]
+		c.Extra = append(c.Extra, parts[1:]...)
+		return nil
+	}
+
+	type component Component
+	return json.Unmarshal(data, (*component)(c))
+}
+
+// String returns the text without formatting. Translated components are
+// reduced to their arguments, or to the translation key when they have
+// none, e.g. "entity.minecraft.zombie".
+func (c Component) String() string {
+	var b strings.Builder
+	c.writeText(&b)
+	return b.String()
+}
+
+func (c *Component) writeText(b *strings.Builder) {
+	b.WriteString(c.Text)
+	switch {
+	case len(c.With) > 0:
+		for i := range c.With {
+			c.With[i].writeText(b)
+		}
+	case c.Translate != "" && c.Text == "":
+		b.WriteString(c.Translate)
+	case c.Keybind != "":
+		b.WriteString(c.Keybind)
+	case c.Score != nil:
+		b.WriteString(c.Score.Value)
+	case c.Selector != "":
+		b.WriteString(c.Selector)
+	}
+	for i := range c.Extra {
+		c.Extra[i].writeText(b)
+	}
+}
+
+// Chat returns the component as a Chat.
+func (c Component) Chat() Chat {
+	data, _ := json.Marshal(c)
+	return Chat(data)
+}
+
+// TextChat returns a Chat with plain text s.
+func TextChat(s string) Chat {
+	return Component{Text: s}.Chat()
+}
+
+// Component decodes the JSON of the chat. An empty chat is an empty
+// component.
+func (c Chat) Component() (Component, error) {
+	var component Component
+	if c == "" {
+		return component, nil
+	}
+	err := json.Unmarshal([]byte(c), &component)
+	return component, err
+}
+
+// PlainText returns the text of the chat without formatting. Chat that is
+// not JSON is returned as is.
+func (c Chat) PlainText() string {
+	component, err := c.Component()
+	if err != nil {
+		return string(c)
+	}
+	return component.String()
+}
@@ -0,0 +1,55 @@
+package proto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComponent(t *testing.T) {
+	bold := true
+	tests := []struct {
+		chat  Chat
+		want  Component
+		plain string
+	}{
+		{`"plain"`, Component{Text: "plain"}, "plain"},
+		{
+			`[{"text":"a","bold":true},"b"]`,
+			Component{Text: "a", Bold: &bold, Extra: []Component{{Text: "b"}}},
+			"ab",
+		},
+		{
+			`{"translate":"death.attack.mob","with":[{"text":"Bot"},{"translate":"entity.minecraft.zombie"},3]}`,
+			Component{Translate: "death.attack.mob", With: []Component{
+				{Text: "Bot"}, {Translate: "entity.minecraft.zombie"}, {Text: "3"},
+			}},
+			"Botentity.minecraft.zombie3",
+		},
+		{
+			`{"text":"","extra":[{"text":"Kills: ","color":"gold"},{"score":{"name":"Bob","objective":"kills","value":"7"}}]}`,
+			Component{Extra: []Component{
+				{Text: "Kills: ", Color: "gold"},
+				{Score: &Score{Name: "Bob", Objective: "kills", Value: "7"}},
+			}},
+			"Kills: 7",
+		},
+		{"", Component{}, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.chat.Component()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.chat, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.chat, got, tt.want)
+		}
+		if plain := tt.chat.PlainText(); plain != tt.plain {
+			t.Errorf("%s: got text %q, want %q", tt.chat, plain, tt.plain)
+		}
+	}
+
+	if chat := TextChat(`say "hi"`); chat != `{"text":"say \"hi\""}` {
+		t.Errorf("got %s", chat)
+	}
+}
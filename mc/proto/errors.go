@@ -3,5 +3,6 @@ package proto
 import "errors"
 
 var (
-	ErrVarIntTooBig = errors.New("varint is too big")
+	ErrVarIntTooBig  = errors.New("varint is too big")
+	ErrVarLongTooBig = errors.New("varlong is too big")
 )
@@ -0,0 +1,76 @@
+package proto
+
+import "io"
+
+// Boss Bar actions.
+const (
+	BossBarAdd          = 0
+	BossBarRemove       = 1
+	BossBarUpdateHealth = 2
+	BossBarUpdateTitle  = 3
+	BossBarUpdateStyle  = 4
+	BossBarUpdateFlags  = 5
+)
+
+// BossBarResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Boss_Bar
+// Only the fields of Action are sent.
+type BossBarResponse struct {
+	UUID     Uuid
+	Action   VarInt
+	Title    Chat
+	Health   Float  // Health is from 0 to 1
+	Color    VarInt // Color is 0 pink, 1 blue, 2 red, 3 green, 4 yellow, 5 purple or 6 white
+	Division VarInt // Division is 0 for no notches, then 6, 10, 12 or 20 notches
+	Flags    UByte
+}
+
+func (b *BossBarResponse) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := readAll(r, &b.UUID, &b.Action)
+	if err != nil {
+		return nn, err
+	}
+
+	var values []io.ReaderFrom
+	switch b.Action {
+	case BossBarAdd:
+		values = []io.ReaderFrom{&b.Title, &b.Health, &b.Color, &b.Division, &b.Flags}
+	case BossBarUpdateHealth:
+		values = []io.ReaderFrom{&b.Health}
+	case BossBarUpdateTitle:
+		values = []io.ReaderFrom{&b.Title}
+	case BossBarUpdateStyle:
+		values = []io.ReaderFrom{&b.Color, &b.Division}
+	case BossBarUpdateFlags:
+		values = []io.ReaderFrom{&b.Flags}
+	}
+
+	n, err := readAll(r, values...)
+	return nn + n, err
+}
+
+type SetTitleTextResponse struct {
+	Text Chat
+}
+
+type SetSubtitleTextResponse struct {
+	Text Chat
+}
+
+type SetTitleAnimationTimesResponse struct {
+	FadeIn  Int // FadeIn, Stay and FadeOut are in ticks
+	Stay    Int
+	FadeOut Int
+}
+
+type ClearTitlesResponse struct {
+	Reset Bool // Reset also restores the default animation times
+}
+
+type SetActionBarTextResponse struct {
+	Text Chat
+}
+
+type SetTabListHeaderAndFooterResponse struct {
+	Header Chat
+	Footer Chat
+}
@@ -0,0 +1,38 @@
+package proto
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReadBossBar(t *testing.T) {
+	id := Uuid{1, 2}
+	flags := UByte(0x01)
+	tests := []struct {
+		values []any
+		want   BossBarResponse
+	}{
+		{
+			[]any{NewVarInt(BossBarAdd), NewString(`"Wither"`), NewFloat(0.5), NewVarInt(5), NewVarInt(2), &flags},
+			BossBarResponse{UUID: id, Action: BossBarAdd, Title: `"Wither"`, Health: 0.5, Color: 5, Division: 2, Flags: 0x01},
+		},
+		{
+			[]any{NewVarInt(BossBarRemove)},
+			BossBarResponse{UUID: id, Action: BossBarRemove},
+		},
+		{
+			[]any{NewVarInt(BossBarUpdateHealth), NewFloat(0.25)},
+			BossBarResponse{UUID: id, Action: BossBarUpdateHealth, Health: 0.25},
+		},
+		{
+			[]any{NewVarInt(BossBarUpdateStyle), NewVarInt(1), NewVarInt(4)},
+			BossBarResponse{UUID: id, Action: BossBarUpdateStyle, Color: 1, Division: 4},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			testScan(t, append([]any{&id}, tt.values...), &BossBarResponse{}, &tt.want)
+		})
+	}
+}
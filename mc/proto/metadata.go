@@ -0,0 +1,296 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+)
+
+// MetadataType https://wiki.vg/index.php?title=Entity_metadata&oldid=18375#Entity_Metadata_Format
+type MetadataType VarInt
+
+const (
+	MetaByte MetadataType = iota
+	MetaVarInt
+	MetaVarLong
+	MetaFloat
+	MetaString
+	MetaChat
+	MetaOptChat
+	MetaSlot
+	MetaBool
+	MetaRotation
+	MetaPosition
+	MetaOptPosition
+	MetaDirection
+	MetaOptUuid
+	MetaBlockID
+	MetaOptBlockID
+	MetaNBT
+	MetaParticle
+	MetaVillagerData
+	MetaOptVarInt
+	MetaPose
+	MetaCatVariant
+	MetaFrogVariant
+	MetaOptGlobalPos
+	MetaPaintingVariant
+	MetaSnifferState
+	MetaVector3
+	MetaQuaternion
+)
+
+// metadataEnd terminates the list of entries in the Set Entity Metadata packet.
+const metadataEnd = 0xff
+
+type Direction VarInt
+
+const (
+	DirectionDown Direction = iota
+	DirectionUp
+	DirectionNorth
+	DirectionSouth
+	DirectionWest
+	DirectionEast
+)
+
+type Pose VarInt
+
+const (
+	PoseStanding Pose = iota
+	PoseFallFlying
+	PoseSleeping
+	PoseSwimming
+	PoseSpinAttack
+	PoseSneaking
+	PoseLongJumping
+	PoseDying
+	PoseCroaking
+	PoseUsingTongue
+	PoseSitting
+	PoseRoaring
+	PoseSniffing
+	PoseEmerging
+	PoseDigging
+)
+
+type Rotation struct {
+	X, Y, Z Float
+}
+
+type Vector3 struct {
+	X, Y, Z Float
+}
+
+type Quaternion struct {
+	X, Y, Z, W Float
+}
+
+type VillagerData struct {
+	Type       VarInt
+	Profession VarInt
+	Level      VarInt
+}
+
+type GlobalPos struct {
+	Dimension String
+	Position  Position
+}
+
+// MetadataEntry holds a single decoded metadata value. The Go type of Value
+// depends on Type:
+//
+//	MetaByte                            Byte
+//	MetaVarInt, MetaBlockID             VarInt
+//	MetaVarLong                         VarLong
+//	MetaFloat                           Float
+//	MetaString                          String
+//	MetaChat                            Chat
+//	MetaOptChat                         *Chat
+//	MetaSlot                            Slot
+//	MetaBool                            Bool
+//	MetaRotation                        Rotation
+//	MetaPosition                        Position
+//	MetaOptPosition                     *Position
+//	MetaDirection                       Direction
+//	MetaOptUuid                         *Uuid
+//	MetaOptBlockID                      VarInt (0 means absent, i.e. air)
+//	MetaNBT                             NBT
+//	MetaParticle                        Particle
+//	MetaVillagerData                    VillagerData
+//	MetaOptVarInt                       *VarInt
+//	MetaPose                            Pose
+//	MetaCatVariant, MetaFrogVariant,
+//	MetaPaintingVariant, MetaSnifferState VarInt
+//	MetaOptGlobalPos                    *GlobalPos
+//	MetaVector3                         Vector3
+//	MetaQuaternion                      Quaternion
+type MetadataEntry struct {
+	Index UByte
+	Type  MetadataType
+	Value any
+}
+
+// EntityMetadata is the list of entries sent in Set Entity Metadata.
+type EntityMetadata []MetadataEntry
+
+func (m *EntityMetadata) ReadFrom(r io.Reader) (int64, error) {
+	*m = (*m)[:0]
+	nn := int64(0)
+	for {
+		var entry MetadataEntry
+		n, err := entry.Index.ReadFrom(r)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+		if entry.Index == metadataEnd {
+			return nn, nil
+		}
+
+		var typ VarInt
+		n, err = typ.ReadFrom(r)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+		entry.Type = MetadataType(typ)
+
+		entry.Value, n, err = readMetadataValue(r, entry.Type)
+		nn += n
+		if err != nil {
+			return nn, fmt.Errorf("cannot read metadata entry %d: %w", entry.Index, err)
+		}
+		*m = append(*m, entry)
+	}
+}
+
+func readMetadataValue(r io.Reader, typ MetadataType) (any, int64, error) {
+	switch typ {
+	case MetaByte:
+		var v Byte
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaVarInt, MetaBlockID, MetaOptBlockID, MetaCatVariant, MetaFrogVariant, MetaPaintingVariant, MetaSnifferState:
+		var v VarInt
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaVarLong:
+		var v VarLong
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaFloat:
+		var v Float
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaString:
+		var v String
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaChat:
+		var v Chat
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaOptChat:
+		var v *Chat
+		n, err := readOptional(r, func() io.ReaderFrom {
+			v = new(Chat)
+			return v
+		})
+		return v, n, err
+	case MetaSlot:
+		var v Slot
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaBool:
+		var v Bool
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaRotation:
+		var v Rotation
+		n, err := readAll(r, &v.X, &v.Y, &v.Z)
+		return v, n, err
+	case MetaPosition:
+		var v Position
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaOptPosition:
+		var v *Position
+		n, err := readOptional(r, func() io.ReaderFrom {
+			v = new(Position)
+			return v
+		})
+		return v, n, err
+	case MetaDirection:
+		var v VarInt
+		n, err := v.ReadFrom(r)
+		return Direction(v), n, err
+	case MetaOptUuid:
+		var v *Uuid
+		n, err := readOptional(r, func() io.ReaderFrom {
+			v = new(Uuid)
+			return v
+		})
+		return v, n, err
+	case MetaNBT:
+		var v NBT
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaParticle:
+		var v Particle
+		n, err := v.ReadFrom(r)
+		return v, n, err
+	case MetaVillagerData:
+		var v VillagerData
+		n, err := readAll(r, &v.Type, &v.Profession, &v.Level)
+		return v, n, err
+	case MetaOptVarInt:
+		var v VarInt
+		n, err := v.ReadFrom(r)
+		if err != nil || v == 0 {
+			return (*VarInt)(nil), n, err
+		}
+		v--
+		return &v, n, nil
+	case MetaPose:
+		var v VarInt
+		n, err := v.ReadFrom(r)
+		return Pose(v), n, err
+	case MetaOptGlobalPos:
+		var v *GlobalPos
+		n, err := readOptional(r, func() io.ReaderFrom {
+			v = new(GlobalPos)
+			return v
+		})
+		return v, n, err
+	case MetaVector3:
+		var v Vector3
+		n, err := readAll(r, &v.X, &v.Y, &v.Z)
+		return v, n, err
+	case MetaQuaternion:
+		var v Quaternion
+		n, err := readAll(r, &v.X, &v.Y, &v.Z, &v.W)
+		return v, n, err
+	}
+	return nil, 0, fmt.Errorf("unknown metadata type: %d", typ)
+}
+
+// readOptional reads a Bool prefix and, if it is true, the value returned
+// by alloc.
+func readOptional(r io.Reader, alloc func() io.ReaderFrom) (int64, error) {
+	var present Bool
+	nn, err := present.ReadFrom(r)
+	if err != nil || !present {
+		return nn, err
+	}
+
+	n, err := alloc().ReadFrom(r)
+	return nn + n, err
+}
+
+func (g *GlobalPos) ReadFrom(r io.Reader) (int64, error) {
+	return readAll(r, &g.Dimension, &g.Position)
+}
+
+func (g *GlobalPos) WriteTo(w io.Writer) (int64, error) {
+	return writeAll(w, &g.Dimension, &g.Position)
+}
@@ -0,0 +1,84 @@
+package proto
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReadEntityMetadata(t *testing.T) {
+	input := []byte{
+		0x00, 0x00, 0x02, // flags: Byte 2 (crouching)
+		0x02, 0x06, 0x01, 0x0e, '{', '"', 't', 'e', 'x', 't', '"', ':', '"', 'B', 'o', 'b', '"', '}', // custom name: OptChat
+		0x06, 0x14, 0x05, // pose: sneaking
+		0x08, 0x07, 0x01, 0x8c, 0x01, 0x03, 0x00, // item: Slot 140 x3 without nbt
+		0x09, 0x03, 0x41, 0xa0, 0x00, 0x00, // health: Float 20
+		0x0e, 0x0b, 0x00, // bed: empty OptPosition
+		0x0f, 0x13, 0x05, // OptVarInt 4
+		metadataEnd,
+	}
+
+	var meta EntityMetadata
+	if _, err := meta.ReadFrom(bytes.NewBuffer(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	name := Chat(`{"text":"Bob"}`)
+	four := VarInt(4)
+	want := EntityMetadata{
+		{Index: 0, Type: MetaByte, Value: Byte(2)},
+		{Index: 2, Type: MetaOptChat, Value: &name},
+		{Index: 6, Type: MetaPose, Value: PoseSneaking},
+		{Index: 8, Type: MetaSlot, Value: Slot{Present: true, ItemID: 140, Count: 3}},
+		{Index: 9, Type: MetaFloat, Value: Float(20)},
+		{Index: 14, Type: MetaOptPosition, Value: (*Position)(nil)},
+		{Index: 15, Type: MetaOptVarInt, Value: &four},
+	}
+	if !reflect.DeepEqual(want, meta) {
+		t.Errorf("Want: %#v, Got: %#v", want, meta)
+	}
+}
+
+func TestNBTRoundTrip(t *testing.T) {
+	want := NBT{Name: "", Value: map[string]any{
+		"display": map[string]any{
+			"Name": `{"text":"Sword"}`,
+			"Lore": []any{"a", "b"},
+		},
+		"Damage":  int32(12),
+		"Unbreak": int8(1),
+		"Longs":   []int64{1, -1},
+		"Scale":   float64(0.5),
+		"Empty":   []any{},
+	}}
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := want.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got NBT
+	if _, err := got.ReadFrom(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %#v, Got: %#v", want, got)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%d bytes left unread", buf.Len())
+	}
+}
+
+func TestNBTHugeLengths(t *testing.T) {
+	for _, data := range [][]byte{
+		{TagCompound, 0, 0, TagList, 0, 1, 'l', TagInt, 0x7f, 0xff, 0xff, 0xff, 0, 0, 0, 1},
+		{TagCompound, 0, 0, TagLongArray, 0, 1, 'l', 0x7f, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 1},
+		{TagCompound, 0, 0, TagIntArray, 0, 1, 'l', 0x7f, 0xff, 0xff, 0xff},
+		{TagCompound, 0, 0, TagByteArray, 0, 1, 'b', 0x7f, 0xff, 0xff, 0xff, 1, 2, 3},
+	} {
+		var got NBT
+		if _, err := got.ReadFrom(bytes.NewReader(data)); err == nil {
+			t.Errorf("%x: expected an error for truncated data", data)
+		}
+	}
+}
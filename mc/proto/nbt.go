@@ -0,0 +1,362 @@
+package proto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	TagEnd byte = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+// NBT is a named binary tag as sent by 1.20.1 servers, i.e. with the root
+// name included. Value holds Go representations of the tags:
+//
+//	TAG_Byte       int8
+//	TAG_Short      int16
+//	TAG_Int        int32
+//	TAG_Long       int64
+//	TAG_Float      float32
+//	TAG_Double     float64
+//	TAG_Byte_Array []int8
+//	TAG_String     string
+//	TAG_List       []any
+//	TAG_Compound   map[string]any
+//	TAG_Int_Array  []int32
+//	TAG_Long_Array []int64
+//
+// A nil Value stands for a single TAG_End, which the protocol uses to mark
+// missing data.
+type NBT struct {
+	Name  string
+	Value any
+}
+
+// Compound returns the root value as a compound or nil if it is not one.
+func (t NBT) Compound() map[string]any {
+	c, _ := t.Value.(map[string]any)
+	return c
+}
+
+func (t *NBT) WriteTo(w io.Writer) (int64, error) {
+	nw := &nbtWriter{w: w}
+	if t.Value == nil {
+		nw.byte(TagEnd)
+		return nw.n, nw.err
+	}
+
+	id, err := nbtTagID(t.Value)
+	if err != nil {
+		return 0, err
+	}
+	nw.byte(id)
+	nw.string(t.Name)
+	nw.payload(t.Value)
+	return nw.n, nw.err
+}
+
+func (t *NBT) ReadFrom(r io.Reader) (int64, error) {
+	nr := &nbtReader{r: r}
+	id := nr.byte()
+	if nr.err != nil || id == TagEnd {
+		t.Name, t.Value = "", nil
+		return nr.n, nr.err
+	}
+
+	t.Name = nr.string()
+	t.Value = nr.payload(id, 0)
+	return nr.n, nr.err
+}
+
+// nbtMaxDepth is the nesting limit used by the vanilla client.
+const nbtMaxDepth = 512
+
+type nbtReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (nr *nbtReader) read(size int) []byte {
+	if nr.err != nil {
+		return nil
+	}
+	buf := make([]byte, size)
+	n, err := readFull(nr.r, buf)
+	nr.n += n
+	nr.err = err
+	return buf
+}
+
+// readBytes reads size bytes of a length-prefixed payload. The length is not
+// trusted for allocation, a malformed tag runs out of data first.
+func (nr *nbtReader) readBytes(size int) []byte {
+	if nr.err != nil {
+		return nil
+	}
+	buf, err := io.ReadAll(io.LimitReader(nr.r, int64(size)))
+	nr.n += int64(len(buf))
+	if err == nil && len(buf) != size {
+		err = io.ErrUnexpectedEOF
+	}
+	nr.err = err
+	return buf
+}
+
+func (nr *nbtReader) byte() byte {
+	if buf := nr.read(1); buf != nil {
+		return buf[0]
+	}
+	return 0
+}
+
+func (nr *nbtReader) uint16() uint16 {
+	if buf := nr.read(2); buf != nil {
+		return binary.BigEndian.Uint16(buf)
+	}
+	return 0
+}
+
+func (nr *nbtReader) uint32() uint32 {
+	if buf := nr.read(4); buf != nil {
+		return binary.BigEndian.Uint32(buf)
+	}
+	return 0
+}
+
+func (nr *nbtReader) uint64() uint64 {
+	if buf := nr.read(8); buf != nil {
+		return binary.BigEndian.Uint64(buf)
+	}
+	return 0
+}
+
+func (nr *nbtReader) string() string {
+	return string(nr.readBytes(int(nr.uint16())))
+}
+
+func (nr *nbtReader) length() int {
+	l := int32(nr.uint32())
+	if l < 0 && nr.err == nil {
+		nr.err = fmt.Errorf("negative nbt array length: %d", l)
+	}
+	if nr.err != nil {
+		return 0
+	}
+	return int(l)
+}
+
+func (nr *nbtReader) payload(id byte, depth int) any {
+	if depth > nbtMaxDepth {
+		nr.err = fmt.Errorf("nbt is nested too deep")
+	}
+	if nr.err != nil {
+		return nil
+	}
+
+	switch id {
+	case TagByte:
+		return int8(nr.byte())
+	case TagShort:
+		return int16(nr.uint16())
+	case TagInt:
+		return int32(nr.uint32())
+	case TagLong:
+		return int64(nr.uint64())
+	case TagFloat:
+		return math.Float32frombits(nr.uint32())
+	case TagDouble:
+		return math.Float64frombits(nr.uint64())
+	case TagByteArray:
+		buf := nr.readBytes(nr.length())
+		out := make([]int8, len(buf))
+		for i, b := range buf {
+			out[i] = int8(b)
+		}
+		return out
+	case TagString:
+		return nr.string()
+	case TagList:
+		elem := nr.byte()
+		size := nr.length()
+		// lengths are not trusted for preallocation, see readBytes
+		out := []any{}
+		for i := 0; i < size && nr.err == nil; i++ {
+			out = append(out, nr.payload(elem, depth+1))
+		}
+		return out
+	case TagCompound:
+		out := make(map[string]any)
+		for nr.err == nil {
+			elem := nr.byte()
+			if elem == TagEnd {
+				break
+			}
+			name := nr.string()
+			out[name] = nr.payload(elem, depth+1)
+		}
+		return out
+	case TagIntArray:
+		size := nr.length()
+		out := []int32{}
+		for i := 0; i < size && nr.err == nil; i++ {
+			out = append(out, int32(nr.uint32()))
+		}
+		return out
+	case TagLongArray:
+		size := nr.length()
+		out := []int64{}
+		for i := 0; i < size && nr.err == nil; i++ {
+			out = append(out, int64(nr.uint64()))
+		}
+		return out
+	}
+
+	nr.err = fmt.Errorf("unknown nbt tag id: %d", id)
+	return nil
+}
+
+type nbtWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (nw *nbtWriter) write(buf []byte) {
+	if nw.err != nil {
+		return
+	}
+	n, err := int64Wrap(nw.w.Write(buf))
+	nw.n += n
+	nw.err = err
+}
+
+func (nw *nbtWriter) byte(b byte) {
+	nw.write([]byte{b})
+}
+
+func (nw *nbtWriter) uint16(v uint16) {
+	nw.write(binary.BigEndian.AppendUint16(nil, v))
+}
+
+func (nw *nbtWriter) uint32(v uint32) {
+	nw.write(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (nw *nbtWriter) uint64(v uint64) {
+	nw.write(binary.BigEndian.AppendUint64(nil, v))
+}
+
+func (nw *nbtWriter) string(s string) {
+	nw.uint16(uint16(len(s)))
+	nw.write([]byte(s))
+}
+
+func (nw *nbtWriter) payload(v any) {
+	switch v := v.(type) {
+	case int8:
+		nw.byte(byte(v))
+	case int16:
+		nw.uint16(uint16(v))
+	case int32:
+		nw.uint32(uint32(v))
+	case int64:
+		nw.uint64(uint64(v))
+	case float32:
+		nw.uint32(math.Float32bits(v))
+	case float64:
+		nw.uint64(math.Float64bits(v))
+	case []int8:
+		nw.uint32(uint32(len(v)))
+		buf := make([]byte, len(v))
+		for i, b := range v {
+			buf[i] = byte(b)
+		}
+		nw.write(buf)
+	case string:
+		nw.string(v)
+	case []any:
+		elem := TagEnd
+		if len(v) > 0 {
+			id, err := nbtTagID(v[0])
+			if err != nil {
+				nw.err = err
+				return
+			}
+			elem = id
+		}
+		nw.byte(elem)
+		nw.uint32(uint32(len(v)))
+		for _, e := range v {
+			nw.payload(e)
+		}
+	case map[string]any:
+		for name, e := range v {
+			id, err := nbtTagID(e)
+			if err != nil {
+				nw.err = err
+				return
+			}
+			nw.byte(id)
+			nw.string(name)
+			nw.payload(e)
+		}
+		nw.byte(TagEnd)
+	case []int32:
+		nw.uint32(uint32(len(v)))
+		for _, e := range v {
+			nw.uint32(uint32(e))
+		}
+	case []int64:
+		nw.uint32(uint32(len(v)))
+		for _, e := range v {
+			nw.uint64(uint64(e))
+		}
+	default:
+		nw.err = fmt.Errorf("unsupported nbt value type: %T", v)
+	}
+}
+
+func nbtTagID(v any) (byte, error) {
+	switch v.(type) {
+	case int8:
+		return TagByte, nil
+	case int16:
+		return TagShort, nil
+	case int32:
+		return TagInt, nil
+	case int64:
+		return TagLong, nil
+	case float32:
+		return TagFloat, nil
+	case float64:
+		return TagDouble, nil
+	case []int8:
+		return TagByteArray, nil
+	case string:
+		return TagString, nil
+	case []any:
+		return TagList, nil
+	case map[string]any:
+		return TagCompound, nil
+	case []int32:
+		return TagIntArray, nil
+	case []int64:
+		return TagLongArray, nil
+	}
+	return TagEnd, fmt.Errorf("unsupported nbt value type: %T", v)
+}
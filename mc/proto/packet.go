@@ -3,6 +3,7 @@ package proto
 import (
 	"bytes"
 	"compress/zlib"
+	"fmt"
 	"io"
 	"reflect"
 )
@@ -96,22 +97,9 @@ func NewCompressPacketFromReader(r io.Reader) (Packet, error) {
 
 func (p *Packet) Append(s ...any) error {
 	buf := bytes.NewBuffer(nil)
-	input := []reflect.Value{reflect.ValueOf(buf)}
-
 	for _, v := range s {
-		r := reflect.ValueOf(v)
-		if r.Kind() == reflect.Pointer {
-			r = r.Elem()
-		}
-
-		if r.Kind() == reflect.Struct {
-			for i := 0; i < r.NumField(); i++ {
-				field := r.Field(i).Addr()
-				method := field.MethodByName("WriteTo")
-				method.Call(input)
-			}
-		} else {
-			_, _ = v.(io.WriterTo).WriteTo(buf)
+		if err := appendValue(buf, v); err != nil {
+			return err
 		}
 	}
 
@@ -119,24 +107,57 @@ func (p *Packet) Append(s ...any) error {
 	return nil
 }
 
+// appendValue writes v using its WriteTo method. Structs without one are
+// written field by field.
+func appendValue(w io.Writer, v any) error {
+	if wt, ok := v.(io.WriterTo); ok {
+		_, err := wt.WriteTo(w)
+		return err
+	}
+
+	r := reflect.ValueOf(v)
+	if r.Kind() == reflect.Pointer {
+		r = r.Elem()
+	}
+	if r.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot write value of type %T", v)
+	}
+
+	for i := 0; i < r.NumField(); i++ {
+		if err := appendValue(w, r.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("cannot write field %s: %w", r.Type().Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
 func (p *Packet) Scan(s ...any) error {
 	buf := bytes.NewBuffer(p.Data)
-	input := []reflect.Value{reflect.ValueOf(buf)}
-
 	for _, v := range s {
-		r := reflect.ValueOf(v)
-		if r.Kind() == reflect.Pointer {
-			r = r.Elem()
+		if err := scanValue(buf, v); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+// scanValue reads v using its ReadFrom method. Structs without one are
+// read field by field.
+func scanValue(r io.Reader, v any) error {
+	if rf, ok := v.(io.ReaderFrom); ok {
+		_, err := rf.ReadFrom(r)
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot read value of type %T", v)
+	}
+	rv = rv.Elem()
 
-		if r.Kind() == reflect.Struct {
-			for i := 0; i < r.NumField(); i++ {
-				field := r.Field(i).Addr()
-				method := field.MethodByName("ReadFrom")
-				method.Call(input)
-			}
-		} else {
-			_, _ = v.(io.ReaderFrom).ReadFrom(buf)
+	for i := 0; i < rv.NumField(); i++ {
+		if err := scanValue(r, rv.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("cannot read field %s: %w", rv.Type().Field(i).Name, err)
 		}
 	}
 	return nil
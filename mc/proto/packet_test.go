@@ -0,0 +1,30 @@
+package proto
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// testScan encodes values one after another, scans them into got and
+// compares it with want. got and want are pointers to the same type.
+func testScan(t *testing.T, values []any, got, want any) {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	for _, v := range values {
+		if err := appendValue(buf, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := scanValue(buf, got); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%d bytes left unread", buf.Len())
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %#v, Got: %#v", want, got)
+	}
+}
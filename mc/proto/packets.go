@@ -25,8 +25,15 @@ type SetHealthResponse struct {
 
 type CombatDeathResponse struct {
 	PlayerID VarInt
-	Message  String // Chat
-	// Message  Chat // Currently I don't know how to handle this type
+	Message  Chat
+}
+
+type DamageEventResponse struct {
+	EntityID       VarInt
+	SourceTypeID   VarInt
+	SourceCauseID  VarInt // SourceCauseID is the entity ID of the attacker plus one, 0 if none
+	SourceDirectID VarInt // SourceDirectID is the entity ID of e.g. the arrow plus one, 0 if none
+	SourcePosition Optional[struct{ X, Y, Z Double }]
 }
 
 type ClientCommandActionEnum VarInt
@@ -43,3 +50,254 @@ type ClientCommandRequest struct {
 type ReceivePlayerDisconnect struct {
 	Reason String
 }
+
+type SpawnEntityResponse struct {
+	EntityID   VarInt
+	EntityUUID Uuid
+	Type       VarInt
+	X, Y, Z    Double
+	Pitch      Angle
+	Yaw        Angle
+	HeadYaw    Angle
+	Data       VarInt
+	VelocityX  Short
+	VelocityY  Short
+	VelocityZ  Short
+}
+
+type SpawnExperienceOrbResponse struct {
+	EntityID VarInt
+	X, Y, Z  Double
+	Count    Short
+}
+
+type SpawnPlayerResponse struct {
+	EntityID   VarInt
+	PlayerUUID Uuid
+	X, Y, Z    Double
+	Yaw        Angle
+	Pitch      Angle
+}
+
+type RemoveEntitiesResponse struct {
+	EntityIDs Array[VarInt]
+}
+
+type UpdateEntityPositionResponse struct {
+	EntityID               VarInt
+	DeltaX, DeltaY, DeltaZ Short
+	OnGround               Bool
+}
+
+type UpdateEntityPositionAndRotationResponse struct {
+	EntityID               VarInt
+	DeltaX, DeltaY, DeltaZ Short
+	Yaw                    Angle
+	Pitch                  Angle
+	OnGround               Bool
+}
+
+type UpdateEntityRotationResponse struct {
+	EntityID VarInt
+	Yaw      Angle
+	Pitch    Angle
+	OnGround Bool
+}
+
+type TeleportEntityResponse struct {
+	EntityID VarInt
+	X, Y, Z  Double
+	Yaw      Angle
+	Pitch    Angle
+	OnGround Bool
+}
+
+type SetHeadRotationResponse struct {
+	EntityID VarInt
+	HeadYaw  Angle
+}
+
+type SetEntityVelocityResponse struct {
+	EntityID                        VarInt
+	VelocityX, VelocityY, VelocityZ Short
+}
+
+type SetEntityMetadataResponse struct {
+	EntityID VarInt
+	Metadata EntityMetadata
+}
+
+type AttributeModifier struct {
+	UUID      Uuid
+	Amount    Double
+	Operation Byte
+}
+
+type AttributeProperty struct {
+	Key       String
+	Value     Double
+	Modifiers Array[AttributeModifier]
+}
+
+type UpdateAttributesResponse struct {
+	EntityID   VarInt
+	Properties Array[AttributeProperty]
+}
+
+type EntityEffectResponse struct {
+	EntityID    VarInt
+	EffectID    VarInt
+	Amplifier   Byte
+	Duration    VarInt // Duration in ticks, -1 for infinity
+	Flags       Byte
+	FactorCodec Optional[NBT]
+}
+
+type RemoveEntityEffectResponse struct {
+	EntityID VarInt
+	EffectID VarInt
+}
+
+type DeathLocation struct {
+	Dimension String
+	Location  Position
+}
+
+type LoginPlayResponse struct {
+	EntityID            Int
+	IsHardcore          Bool
+	GameMode            UByte
+	PreviousGameMode    Byte
+	DimensionNames      Array[String]
+	RegistryCodec       NBT
+	DimensionType       String
+	DimensionName       String
+	HashedSeed          Long
+	MaxPlayers          VarInt
+	ViewDistance        VarInt
+	SimulationDistance  VarInt
+	ReducedDebugInfo    Bool
+	EnableRespawnScreen Bool
+	IsDebug             Bool
+	IsFlat              Bool
+	DeathLocation       Optional[DeathLocation]
+	PortalCooldown      VarInt
+}
+
+type SyncPlayerPositionResponse struct {
+	X, Y, Z    Double
+	Yaw        Float
+	Pitch      Float
+	Flags      Byte
+	TeleportID VarInt
+}
+
+type PlayerAbilitiesResponse struct {
+	Flags       Byte
+	FlyingSpeed Float
+	FOVModifier Float
+}
+
+type SetExperienceResponse struct {
+	ExperienceBar   Float
+	Level           VarInt
+	TotalExperience VarInt
+}
+
+type SetHeldItemResponse struct {
+	Slot Byte
+}
+
+type AcknowledgeBlockChangeResponse struct {
+	Sequence VarInt
+}
+
+type OpenScreenResponse struct {
+	WindowID   VarInt
+	WindowType VarInt
+	Title      Chat
+}
+
+type CloseContainerResponse struct {
+	WindowID UByte
+}
+
+type SetContainerContentResponse struct {
+	WindowID UByte
+	StateID  VarInt
+	Slots    Array[Slot]
+	Carried  Slot
+}
+
+type SetContainerSlotResponse struct {
+	WindowID Byte // WindowID is -1 for the carried item and -2 for the player inventory
+	StateID  VarInt
+	Slot     Short
+	Data     Slot
+}
+
+type SetContainerPropertyResponse struct {
+	WindowID UByte
+	Property Short
+	Value    Short
+}
+
+type UpdateRecipesResponse struct {
+	Recipes Array[Recipe]
+}
+
+type Tag struct {
+	Name    String
+	Entries Array[VarInt]
+}
+
+type RegistryTags struct {
+	Registry String
+	Tags     Array[Tag]
+}
+
+type UpdateTagsResponse struct {
+	Registries Array[RegistryTags]
+}
+
+// RespawnResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Respawn
+type RespawnResponse struct {
+	DimensionType    String
+	DimensionName    String
+	HashedSeed       Long
+	GameMode         UByte
+	PreviousGameMode Byte
+	IsDebug          Bool
+	IsFlat           Bool
+	DataKept         Byte
+	DeathLocation    Optional[DeathLocation]
+	PortalCooldown   VarInt
+}
+
+type LoginSuccessResponse struct {
+	UUID       Uuid
+	Username   String
+	Properties Array[ProfileProperty]
+}
+
+type MerchantOffer struct {
+	Input1          Slot
+	Output          Slot
+	Input2          Slot // Input2 is empty for trades with a single input
+	Disabled        Bool
+	Uses            Int
+	MaxUses         Int
+	XP              Int
+	SpecialPrice    Int // SpecialPrice is added to the count of Input1, e.g. negative for heroes of the village
+	PriceMultiplier Float
+	Demand          Int
+}
+
+type MerchantOffersResponse struct {
+	WindowID   VarInt
+	Offers     Array[MerchantOffer]
+	Level      VarInt
+	Experience VarInt
+	IsVillager Bool // IsVillager is false for wandering traders
+	CanRestock Bool
+}
@@ -0,0 +1,123 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+)
+
+// Particle IDs that carry extra data, see
+// https://wiki.vg/index.php?title=Particles&oldid=18375
+const (
+	ParticleBlock               = VarInt(2)
+	ParticleBlockMarker         = VarInt(3)
+	ParticleDust                = VarInt(14)
+	ParticleDustColorTransition = VarInt(15)
+	ParticleFallingDust         = VarInt(25)
+	ParticleSculkCharge         = VarInt(31)
+	ParticleItem                = VarInt(40)
+	ParticleVibration           = VarInt(41)
+	ParticleShriek              = VarInt(93)
+)
+
+// Particle is a particle ID followed by its type specific data. Data is nil
+// for particles without data, otherwise it is one of:
+//
+//	ParticleBlock, ParticleBlockMarker, ParticleFallingDust  VarInt (block state)
+//	ParticleDust                                            DustParticle
+//	ParticleDustColorTransition                             DustColorTransitionParticle
+//	ParticleSculkCharge                                     Float (roll)
+//	ParticleItem                                            Slot
+//	ParticleVibration                                       VibrationParticle
+//	ParticleShriek                                          VarInt (delay)
+type Particle struct {
+	ID   VarInt
+	Data any
+}
+
+type DustParticle struct {
+	Red, Green, Blue Float
+	Scale            Float
+}
+
+type DustColorTransitionParticle struct {
+	FromRed, FromGreen, FromBlue Float
+	Scale                        Float
+	ToRed, ToGreen, ToBlue       Float
+}
+
+const (
+	VibrationSourceBlock  = VarInt(0)
+	VibrationSourceEntity = VarInt(1)
+)
+
+// VibrationParticle travels from its origin towards either BlockPosition or
+// the entity EntityID depending on SourceType.
+type VibrationParticle struct {
+	SourceType      VarInt
+	BlockPosition   Position
+	EntityID        VarInt
+	EntityEyeHeight Float
+	Ticks           VarInt
+}
+
+func (p *Particle) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := p.ID.ReadFrom(r)
+	if err != nil {
+		return nn, err
+	}
+
+	var n int64
+	switch p.ID {
+	case ParticleBlock, ParticleBlockMarker, ParticleFallingDust, ParticleShriek:
+		var v VarInt
+		n, err = v.ReadFrom(r)
+		p.Data = v
+	case ParticleDust:
+		var v DustParticle
+		n, err = readAll(r, &v.Red, &v.Green, &v.Blue, &v.Scale)
+		p.Data = v
+	case ParticleDustColorTransition:
+		var v DustColorTransitionParticle
+		n, err = readAll(r, &v.FromRed, &v.FromGreen, &v.FromBlue, &v.Scale, &v.ToRed, &v.ToGreen, &v.ToBlue)
+		p.Data = v
+	case ParticleSculkCharge:
+		var v Float
+		n, err = v.ReadFrom(r)
+		p.Data = v
+	case ParticleItem:
+		var v Slot
+		n, err = v.ReadFrom(r)
+		p.Data = v
+	case ParticleVibration:
+		var v VibrationParticle
+		n, err = v.ReadFrom(r)
+		p.Data = v
+	default:
+		p.Data = nil
+	}
+	return nn + n, err
+}
+
+func (v *VibrationParticle) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := v.SourceType.ReadFrom(r)
+	if err != nil {
+		return nn, err
+	}
+
+	var n int64
+	switch v.SourceType {
+	case VibrationSourceBlock:
+		n, err = v.BlockPosition.ReadFrom(r)
+	case VibrationSourceEntity:
+		n, err = readAll(r, &v.EntityID, &v.EntityEyeHeight)
+	default:
+		err = fmt.Errorf("unknown vibration source type: %d", v.SourceType)
+	}
+	nn += n
+	if err != nil {
+		return nn, err
+	}
+
+	n, err = v.Ticks.ReadFrom(r)
+	return nn + n, err
+}
@@ -0,0 +1,93 @@
+package proto
+
+import "io"
+
+// Player Info Update actions, see
+// https://wiki.vg/index.php?title=Protocol&oldid=18375#Player_Info_Update
+const (
+	PlayerInfoAddPlayer         = 0x01
+	PlayerInfoInitializeChat    = 0x02
+	PlayerInfoUpdateGameMode    = 0x04
+	PlayerInfoUpdateListed      = 0x08
+	PlayerInfoUpdateLatency     = 0x10
+	PlayerInfoUpdateDisplayName = 0x20
+)
+
+type ProfileProperty struct {
+	Name      String
+	Value     String
+	Signature Optional[String]
+}
+
+// ChatSession is the public part of a player chat session.
+type ChatSession struct {
+	SessionID          Uuid
+	PublicKeyExpiresAt Long // PublicKeyExpiresAt is in milliseconds since the epoch
+	PublicKey          ByteArray
+	PublicKeySignature ByteArray
+}
+
+// PlayerInfo holds the fields of one player. Only the fields of actions set
+// in PlayerInfoUpdateResponse.Actions are sent.
+type PlayerInfo struct {
+	UUID        Uuid
+	Name        String
+	Properties  Array[ProfileProperty]
+	ChatSession Optional[ChatSession]
+	GameMode    VarInt
+	Listed      Bool
+	Latency     VarInt // Latency is in milliseconds
+	DisplayName Optional[Chat]
+}
+
+type PlayerInfoUpdateResponse struct {
+	Actions Byte
+	Players []PlayerInfo
+}
+
+type PlayerInfoRemoveResponse struct {
+	Players Array[Uuid]
+}
+
+func (p *PlayerInfoUpdateResponse) ReadFrom(r io.Reader) (int64, error) {
+	var count VarInt
+	nn, err := readAll(r, &p.Actions, &count)
+	if err != nil {
+		return nn, err
+	}
+
+	p.Players = p.Players[:0]
+	for i := 0; i < int(count); i++ {
+		var info PlayerInfo
+		n, err := info.read(r, p.Actions)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+		p.Players = append(p.Players, info)
+	}
+	return nn, nil
+}
+
+func (info *PlayerInfo) read(r io.Reader, actions Byte) (int64, error) {
+	values := []io.ReaderFrom{&info.UUID}
+	if actions&PlayerInfoAddPlayer != 0 {
+		values = append(values, &info.Name, &info.Properties)
+	}
+	if actions&PlayerInfoInitializeChat != 0 {
+		values = append(values, &info.ChatSession)
+	}
+	if actions&PlayerInfoUpdateGameMode != 0 {
+		values = append(values, &info.GameMode)
+	}
+	if actions&PlayerInfoUpdateListed != 0 {
+		values = append(values, &info.Listed)
+	}
+	if actions&PlayerInfoUpdateLatency != 0 {
+		values = append(values, &info.Latency)
+	}
+	if actions&PlayerInfoUpdateDisplayName != 0 {
+		values = append(values, &info.DisplayName)
+	}
+	return readAll(r, values...)
+}
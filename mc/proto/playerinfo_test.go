@@ -0,0 +1,23 @@
+package proto
+
+import "testing"
+
+func TestReadPlayerInfoUpdate(t *testing.T) {
+	values := []any{
+		NewByte(PlayerInfoAddPlayer | PlayerInfoUpdateLatency), NewVarInt(1),
+		&Uuid{1, 2}, NewString("Bob"),
+		NewVarInt(1), NewString("textures"), NewString("e30="), NewBool(false),
+		NewVarInt(35),
+	}
+
+	want := PlayerInfoUpdateResponse{
+		Actions: PlayerInfoAddPlayer | PlayerInfoUpdateLatency,
+		Players: []PlayerInfo{{
+			UUID:       Uuid{1, 2},
+			Name:       "Bob",
+			Properties: Array[ProfileProperty]{{Name: "textures", Value: "e30="}},
+			Latency:    35,
+		}},
+	}
+	testScan(t, values, &PlayerInfoUpdateResponse{}, &want)
+}
@@ -0,0 +1,166 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Recipe serializer types, see
+// https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Recipes
+const (
+	RecipeShaped            = "minecraft:crafting_shaped"
+	RecipeShapeless         = "minecraft:crafting_shapeless"
+	RecipeSmelting          = "minecraft:smelting"
+	RecipeBlasting          = "minecraft:blasting"
+	RecipeSmoking           = "minecraft:smoking"
+	RecipeCampfireCooking   = "minecraft:campfire_cooking"
+	RecipeStonecutting      = "minecraft:stonecutting"
+	RecipeSmithingTransform = "minecraft:smithing_transform"
+	RecipeSmithingTrim      = "minecraft:smithing_trim"
+	RecipeDecoratedPot      = "minecraft:crafting_decorated_pot"
+
+	// recipeSpecialPrefix starts the types of special crafting recipes
+	// which only carry a category, e.g. "minecraft:crafting_special_armordye".
+	recipeSpecialPrefix = "minecraft:crafting_special_"
+)
+
+// Ingredient is matched by any of its items.
+type Ingredient = Array[Slot]
+
+// Recipe is a recipe of any serializer type. Fields not used by the type are
+// left zero:
+//
+//	RecipeShaped           Group, Category, Width, Height, Ingredients (row by row), Result, ShowNotification
+//	RecipeShapeless        Group, Category, Ingredients, Result
+//	cooking recipes        Group, Category, Ingredients (one), Result, Experience, CookingTime
+//	RecipeStonecutting     Group, Ingredients (one), Result
+//	RecipeSmithingTransform Ingredients (template, base, addition), Result
+//	RecipeSmithingTrim     Ingredients (template, base, addition)
+//	special recipes        Category
+type Recipe struct {
+	Type             String
+	ID               String
+	Group            String
+	Category         VarInt
+	Width, Height    VarInt
+	Ingredients      []Ingredient
+	Result           Slot
+	ShowNotification Bool
+	Experience       Float
+	CookingTime      VarInt
+}
+
+func (rc *Recipe) ReadFrom(r io.Reader) (int64, error) {
+	*rc = Recipe{}
+	nn, err := readAll(r, &rc.Type, &rc.ID)
+	if err != nil {
+		return nn, err
+	}
+
+	var n int64
+	switch rc.Type {
+	case RecipeShaped:
+		if n, err = readAll(r, &rc.Width, &rc.Height, &rc.Group, &rc.Category); err != nil {
+			return nn + n, err
+		}
+		nn += n
+		if rc.Width < 0 || rc.Height < 0 || rc.Width > 3 || rc.Height > 3 {
+			return nn, fmt.Errorf("invalid shaped recipe size %dx%d", rc.Width, rc.Height)
+		}
+
+		rc.Ingredients = make([]Ingredient, rc.Width*rc.Height)
+		if n, err = rc.readIngredients(r); err != nil {
+			return nn + n, err
+		}
+		nn += n
+		n, err = readAll(r, &rc.Result, &rc.ShowNotification)
+	case RecipeShapeless:
+		if n, err = readAll(r, &rc.Group, &rc.Category); err != nil {
+			return nn + n, err
+		}
+		nn += n
+
+		var ingredients Array[Ingredient]
+		if n, err = ingredients.ReadFrom(r); err != nil {
+			return nn + n, err
+		}
+		nn += n
+		rc.Ingredients = ingredients
+		n, err = rc.Result.ReadFrom(r)
+	case RecipeSmelting, RecipeBlasting, RecipeSmoking, RecipeCampfireCooking:
+		rc.Ingredients = make([]Ingredient, 1)
+		n, err = readAll(r, &rc.Group, &rc.Category, &rc.Ingredients[0], &rc.Result, &rc.Experience, &rc.CookingTime)
+	case RecipeStonecutting:
+		rc.Ingredients = make([]Ingredient, 1)
+		n, err = readAll(r, &rc.Group, &rc.Ingredients[0], &rc.Result)
+	case RecipeSmithingTransform:
+		rc.Ingredients = make([]Ingredient, 3)
+		if n, err = rc.readIngredients(r); err != nil {
+			return nn + n, err
+		}
+		nn += n
+		n, err = rc.Result.ReadFrom(r)
+	case RecipeSmithingTrim:
+		rc.Ingredients = make([]Ingredient, 3)
+		n, err = rc.readIngredients(r)
+	default:
+		if rc.Type != RecipeDecoratedPot && !strings.HasPrefix(string(rc.Type), recipeSpecialPrefix) {
+			return nn, fmt.Errorf("unknown recipe type: %s", rc.Type)
+		}
+		n, err = rc.Category.ReadFrom(r)
+	}
+	return nn + n, err
+}
+
+// readIngredients fills the preallocated Ingredients.
+func (rc *Recipe) readIngredients(r io.Reader) (int64, error) {
+	nn := int64(0)
+	for i := range rc.Ingredients {
+		n, err := rc.Ingredients[i].ReadFrom(r)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+	}
+	return nn, nil
+}
+
+// Update Recipe Book actions.
+const (
+	RecipeBookInit   = VarInt(0)
+	RecipeBookAdd    = VarInt(1)
+	RecipeBookRemove = VarInt(2)
+)
+
+type RecipeBookSettings struct {
+	CraftingOpen, CraftingFilter         Bool
+	SmeltingOpen, SmeltingFilter         Bool
+	BlastFurnaceOpen, BlastFurnaceFilter Bool
+	SmokerOpen, SmokerFilter             Bool
+}
+
+// UpdateRecipeBookResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Recipe_Book
+type UpdateRecipeBookResponse struct {
+	Action   VarInt
+	Settings RecipeBookSettings
+	Recipes  Array[String]
+	// Highlighted is only sent with RecipeBookInit.
+	Highlighted Array[String]
+}
+
+func (u *UpdateRecipeBookResponse) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := readAll(r, &u.Action,
+		&u.Settings.CraftingOpen, &u.Settings.CraftingFilter,
+		&u.Settings.SmeltingOpen, &u.Settings.SmeltingFilter,
+		&u.Settings.BlastFurnaceOpen, &u.Settings.BlastFurnaceFilter,
+		&u.Settings.SmokerOpen, &u.Settings.SmokerFilter,
+		&u.Recipes,
+	)
+	if err != nil || u.Action != RecipeBookInit {
+		return nn, err
+	}
+
+	n, err := u.Highlighted.ReadFrom(r)
+	return nn + n, err
+}
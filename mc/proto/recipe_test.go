@@ -0,0 +1,32 @@
+package proto
+
+import "testing"
+
+func TestReadRecipes(t *testing.T) {
+	planks := Slot{Present: true, ItemID: 23, Count: 1}
+	values := []any{
+		NewVarInt(3),
+		NewString(RecipeShaped), NewString("minecraft:stick"), NewVarInt(1), NewVarInt(2), NewString("sticks"), NewVarInt(0),
+		&Ingredient{planks}, &Ingredient{planks}, &Slot{Present: true, ItemID: 900, Count: 4}, NewBool(true),
+		NewString(RecipeSmelting), NewString("minecraft:glass"), NewString(""), NewVarInt(1),
+		&Ingredient{{Present: true, ItemID: 50, Count: 1}}, &Slot{Present: true, ItemID: 60, Count: 1}, NewFloat(0.1), NewVarInt(200),
+		NewString("minecraft:crafting_special_armordye"), NewString("minecraft:armor_dye"), NewVarInt(3),
+	}
+
+	want := UpdateRecipesResponse{Recipes: Array[Recipe]{
+		{
+			Type: RecipeShaped, ID: "minecraft:stick", Group: "sticks", Width: 1, Height: 2,
+			Ingredients:      []Ingredient{{planks}, {planks}},
+			Result:           Slot{Present: true, ItemID: 900, Count: 4},
+			ShowNotification: true,
+		},
+		{
+			Type: RecipeSmelting, ID: "minecraft:glass", Category: 1,
+			Ingredients: []Ingredient{{{Present: true, ItemID: 50, Count: 1}}},
+			Result:      Slot{Present: true, ItemID: 60, Count: 1},
+			Experience:  0.1, CookingTime: 200,
+		},
+		{Type: "minecraft:crafting_special_armordye", ID: "minecraft:armor_dye", Category: 3},
+	}}
+	testScan(t, values, &UpdateRecipesResponse{}, &want)
+}
@@ -0,0 +1,105 @@
+package proto
+
+import "io"
+
+// Update Objectives modes.
+const (
+	ObjectiveCreate = 0
+	ObjectiveRemove = 1
+	ObjectiveUpdate = 2
+)
+
+// Update Teams modes.
+const (
+	TeamCreate         = 0
+	TeamRemove         = 1
+	TeamUpdate         = 2
+	TeamAddEntities    = 3
+	TeamRemoveEntities = 4
+)
+
+// Update Score actions.
+const (
+	ScoreUpdate = 0
+	ScoreRemove = 1
+)
+
+type DisplayObjectiveResponse struct {
+	Position  Byte
+	ScoreName String // ScoreName is empty to clear the display slot
+}
+
+// UpdateObjectivesResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Objectives
+type UpdateObjectivesResponse struct {
+	Name        String
+	Mode        Byte
+	DisplayName Chat   // DisplayName and Type are not sent for ObjectiveRemove
+	Type        VarInt // Type is 0 for integers and 1 for hearts
+}
+
+func (o *UpdateObjectivesResponse) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := readAll(r, &o.Name, &o.Mode)
+	if err != nil || o.Mode == ObjectiveRemove {
+		return nn, err
+	}
+
+	n, err := readAll(r, &o.DisplayName, &o.Type)
+	return nn + n, err
+}
+
+// TeamInfo holds the team fields sent with TeamCreate and TeamUpdate.
+type TeamInfo struct {
+	DisplayName       Chat
+	FriendlyFlags     Byte   // FriendlyFlags has 0x01 set for friendly fire, 0x02 to see invisible teammates
+	NameTagVisibility String // NameTagVisibility is e.g. "always" or "hideForOtherTeams"
+	CollisionRule     String // CollisionRule is e.g. "always" or "pushOwnTeam"
+	Color             VarInt // Color is a formatting code from 0 (black) to 15 (white), 21 for none
+	Prefix            Chat
+	Suffix            Chat
+}
+
+// UpdateTeamsResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Teams
+type UpdateTeamsResponse struct {
+	Name     String
+	Mode     Byte
+	Info     TeamInfo      // Info is only sent for TeamCreate and TeamUpdate
+	Entities Array[String] // Entities are player names or entity UUIDs, not sent for TeamRemove and TeamUpdate
+}
+
+func (t *UpdateTeamsResponse) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := readAll(r, &t.Name, &t.Mode)
+	if err != nil {
+		return nn, err
+	}
+
+	var values []io.ReaderFrom
+	if t.Mode == TeamCreate || t.Mode == TeamUpdate {
+		info := &t.Info
+		values = append(values, &info.DisplayName, &info.FriendlyFlags, &info.NameTagVisibility,
+			&info.CollisionRule, &info.Color, &info.Prefix, &info.Suffix)
+	}
+	if t.Mode == TeamCreate || t.Mode == TeamAddEntities || t.Mode == TeamRemoveEntities {
+		values = append(values, &t.Entities)
+	}
+
+	n, err := readAll(r, values...)
+	return nn + n, err
+}
+
+// UpdateScoreResponse https://wiki.vg/index.php?title=Protocol&oldid=18375#Update_Score
+type UpdateScoreResponse struct {
+	Entity    String // Entity is a player name or an entity UUID
+	Action    VarInt
+	Objective String // Objective is empty when ScoreRemove resets the scores of all objectives
+	Value     VarInt // Value is not sent for ScoreRemove
+}
+
+func (s *UpdateScoreResponse) ReadFrom(r io.Reader) (int64, error) {
+	nn, err := readAll(r, &s.Entity, &s.Action, &s.Objective)
+	if err != nil || s.Action == ScoreRemove {
+		return nn, err
+	}
+
+	n, err := s.Value.ReadFrom(r)
+	return nn + n, err
+}
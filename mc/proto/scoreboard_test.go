@@ -0,0 +1,65 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestReadScoreboard(t *testing.T) {
+	tests := []struct {
+		values []any
+		got    io.ReaderFrom
+		want   any
+	}{
+		{
+			[]any{NewString("kills"), NewByte(ObjectiveCreate), NewString(`{"text":"Kills"}`), NewVarInt(1)},
+			&UpdateObjectivesResponse{},
+			&UpdateObjectivesResponse{Name: "kills", Mode: ObjectiveCreate, DisplayName: `{"text":"Kills"}`, Type: 1},
+		},
+		{
+			[]any{NewString("kills"), NewByte(ObjectiveRemove)},
+			&UpdateObjectivesResponse{},
+			&UpdateObjectivesResponse{Name: "kills", Mode: ObjectiveRemove},
+		},
+		{
+			[]any{
+				NewString("red"), NewByte(TeamCreate),
+				NewString(`"Red"`), NewByte(0x01), NewString("always"), NewString("never"), NewVarInt(12),
+				NewString(`"[R] "`), NewString(`""`),
+				NewVarInt(2), NewString("Alice"), NewString("Bob"),
+			},
+			&UpdateTeamsResponse{},
+			&UpdateTeamsResponse{
+				Name: "red",
+				Mode: TeamCreate,
+				Info: TeamInfo{
+					DisplayName: `"Red"`, FriendlyFlags: 0x01, NameTagVisibility: "always",
+					CollisionRule: "never", Color: 12, Prefix: `"[R] "`, Suffix: `""`,
+				},
+				Entities: Array[String]{"Alice", "Bob"},
+			},
+		},
+		{
+			[]any{NewString("red"), NewByte(TeamRemoveEntities), NewVarInt(1), NewString("Bob")},
+			&UpdateTeamsResponse{},
+			&UpdateTeamsResponse{Name: "red", Mode: TeamRemoveEntities, Entities: Array[String]{"Bob"}},
+		},
+		{
+			[]any{NewString("Alice"), NewVarInt(ScoreUpdate), NewString("kills"), NewVarInt(7)},
+			&UpdateScoreResponse{},
+			&UpdateScoreResponse{Entity: "Alice", Action: ScoreUpdate, Objective: "kills", Value: 7},
+		},
+		{
+			[]any{NewString("Alice"), NewVarInt(ScoreRemove), NewString("")},
+			&UpdateScoreResponse{},
+			&UpdateScoreResponse{Entity: "Alice", Action: ScoreRemove},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			testScan(t, tt.values, tt.got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package proto
+
+import (
+	"bytes"
+	"io"
+)
+
+// Slot https://wiki.vg/index.php?title=Slot_Data&oldid=18375
+type Slot struct {
+	Present Bool
+	ItemID  VarInt // ItemID and following fields are only sent when Present is true
+	Count   Byte
+	NBT     NBT
+}
+
+// Empty reports whether there is no item in the slot.
+func (s Slot) Empty() bool {
+	return !bool(s.Present) || s.Count <= 0
+}
+
+func (s *Slot) WriteTo(w io.Writer) (int64, error) {
+	buf := bytes.NewBuffer(nil)
+	present := Bool(!s.Empty())
+	_, _ = present.WriteTo(buf)
+	if present {
+		_, _ = s.ItemID.WriteTo(buf)
+		_, _ = s.Count.WriteTo(buf)
+		if _, err := s.NBT.WriteTo(buf); err != nil {
+			return 0, err
+		}
+	}
+	return int64Wrap(w.Write(buf.Bytes()))
+}
+
+func (s *Slot) ReadFrom(r io.Reader) (int64, error) {
+	*s = Slot{}
+	nn, err := s.Present.ReadFrom(r)
+	if err != nil || !s.Present {
+		return nn, err
+	}
+
+	n, err := readAll(r, &s.ItemID, &s.Count, &s.NBT)
+	return nn + n, err
+}
@@ -0,0 +1,102 @@
+package proto
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSlot(t *testing.T) {
+	tests := []struct {
+		slot  Slot
+		bytes []byte
+	}{
+		{Slot{}, []byte{0x00}},
+		{Slot{Present: true, ItemID: 1, Count: 64}, []byte{0x01, 0x01, 0x40, 0x00}},
+		{
+			Slot{Present: true, ItemID: 800, Count: 1, NBT: NBT{Value: map[string]any{"Damage": int32(5)}}},
+			[]byte{0x01, 0xa0, 0x06, 0x01, 0x0a, 0x00, 0x00, 0x03, 0x00, 0x06, 'D', 'a', 'm', 'a', 'g', 'e', 0x00, 0x00, 0x00, 0x05, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		buf := bytes.NewBuffer(nil)
+		if _, err := tt.slot.WriteTo(buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.bytes) {
+			t.Errorf("Want: %#v, Got: %#v", tt.bytes, buf.Bytes())
+		}
+
+		var got Slot
+		if _, err := got.ReadFrom(bytes.NewBuffer(tt.bytes)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.slot, got) {
+			t.Errorf("Want: %#v, Got: %#v", tt.slot, got)
+		}
+	}
+}
+
+func TestSetContainerContent(t *testing.T) {
+	want := SetContainerContentResponse{
+		WindowID: 2,
+		StateID:  7,
+		Slots:    Array[Slot]{{}, {Present: true, ItemID: 1, Count: 3}, {}},
+		Carried:  Slot{Present: true, ItemID: 5, Count: 1},
+	}
+
+	pk := NewPacket(0x12)
+	if err := pk.Append(&want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got SetContainerContentResponse
+	if err := pk.Scan(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %#v, Got: %#v", want, got)
+	}
+}
+
+func TestMerchantOffers(t *testing.T) {
+	want := MerchantOffersResponse{
+		WindowID: 3,
+		Offers: Array[MerchantOffer]{
+			{
+				Input1:          Slot{Present: true, ItemID: 10, Count: 24},
+				Output:          Slot{Present: true, ItemID: 20, Count: 1},
+				Uses:            2,
+				MaxUses:         16,
+				XP:              2,
+				SpecialPrice:    -4,
+				PriceMultiplier: 0.05,
+				Demand:          3,
+			},
+			{
+				Input1:   Slot{Present: true, ItemID: 10, Count: 5},
+				Output:   Slot{Present: true, ItemID: 30, Count: 1},
+				Input2:   Slot{Present: true, ItemID: 40, Count: 1},
+				Disabled: true,
+			},
+		},
+		Level:      2,
+		Experience: 15,
+		IsVillager: true,
+		CanRestock: true,
+	}
+
+	pk := NewPacket(0x2a)
+	if err := pk.Append(&want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got MerchantOffersResponse
+	if err := pk.Scan(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %#v, Got: %#v", want, got)
+	}
+}
@@ -22,8 +22,19 @@ type (
 	Long   int64
 	Float  float32
 	Double float64
+
+	Int     int32
+	VarLong int64
+	Angle   uint8
+	Chat    string // Chat is a JSON text component sent as a string
 )
 
+// Position is a block position packed into a single Long on the wire:
+// x as 26 bits, z as 26 bits and y as 12 bits.
+type Position struct {
+	X, Y, Z int32
+}
+
 func NewVarInt(v int) *VarInt {
 	def := VarInt(v)
 	return &def
@@ -74,10 +85,67 @@ func NewUuidFromBytes(v []byte) *Uuid {
 	return out
 }
 
+func NewInt(v int) *Int {
+	def := Int(v)
+	return &def
+}
+
+func NewVarLong(v int64) *VarLong {
+	def := VarLong(v)
+	return &def
+}
+
+func NewDouble(v float64) *Double {
+	def := Double(v)
+	return &def
+}
+
+func NewFloat(v float32) *Float {
+	def := Float(v)
+	return &def
+}
+
+func NewLong(v int64) *Long {
+	def := Long(v)
+	return &def
+}
+
 func int64Wrap(n int, err error) (int64, error) {
 	return int64(n), err
 }
 
+// readFull is like r.Read but fails with io.ErrUnexpectedEOF if buf cannot be
+// filled completely.
+func readFull(r io.Reader, buf []byte) (int64, error) {
+	return int64Wrap(io.ReadFull(r, buf))
+}
+
+// readAll reads every value in order, stopping at the first error.
+func readAll(r io.Reader, values ...io.ReaderFrom) (int64, error) {
+	nn := int64(0)
+	for _, v := range values {
+		n, err := v.ReadFrom(r)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+	}
+	return nn, nil
+}
+
+// writeAll writes every value in order, stopping at the first error.
+func writeAll(w io.Writer, values ...io.WriterTo) (int64, error) {
+	nn := int64(0)
+	for _, v := range values {
+		n, err := v.WriteTo(w)
+		nn += n
+		if err != nil {
+			return nn, err
+		}
+	}
+	return nn, nil
+}
+
 func (v *VarInt) WriteTo(w io.Writer) (int64, error) {
 	const SegmentBit, ContinueBit uint32 = 0x7F, 0x80
 	val := uint32(*v)
@@ -309,3 +377,214 @@ func (d *Double) ReadFrom(r io.Reader) (int64, error) {
 	*d = Double(math.Float64frombits(binary.BigEndian.Uint64(buf)))
 	return 8, nil
 }
+
+func (i *Int) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, uint32(*i))
+	return int64Wrap(w.Write(buf))
+}
+
+func (i *Int) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, 4)
+	if n, err := readFull(r, buf); err != nil {
+		return n, err
+	}
+
+	*i = Int(binary.BigEndian.Uint32(buf))
+	return 4, nil
+}
+
+func (v *VarLong) WriteTo(w io.Writer) (int64, error) {
+	const SegmentBit, ContinueBit uint64 = 0x7F, 0x80
+	val := uint64(*v)
+
+	buf := make([]byte, 0, 10)
+	for {
+		if (val & ^SegmentBit) == 0 {
+			buf = append(buf, uint8(val))
+			break
+		}
+
+		buf = append(buf, uint8((val&SegmentBit)|ContinueBit))
+		val >>= 7
+	}
+	return int64Wrap(w.Write(buf))
+}
+
+func (v *VarLong) ReadFrom(r io.Reader) (int64, error) {
+	const SegmentBit, ContinueBit uint8 = 0x7F, 0x80
+
+	val, pos, size := uint64(0), 0, int64(0)
+	buf := make([]byte, 1)
+
+	for {
+		n, err := readFull(r, buf)
+		size += n
+		if err != nil {
+			return size, err
+		}
+
+		val |= uint64(buf[0]&SegmentBit) << pos
+
+		if (buf[0] & ContinueBit) == 0 {
+			break
+		}
+		pos += 7
+
+		if pos >= 64 {
+			return size, ErrVarLongTooBig
+		}
+	}
+	*v = VarLong(val)
+	return size, nil
+}
+
+func (a *Angle) WriteTo(w io.Writer) (int64, error) {
+	return int64Wrap(w.Write([]byte{byte(*a)}))
+}
+
+func (a *Angle) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, 1)
+	if n, err := readFull(r, buf); err != nil {
+		return n, err
+	}
+
+	*a = Angle(buf[0])
+	return 1, nil
+}
+
+// Degrees converts angle from 1/256 of a full turn to degrees.
+func (a Angle) Degrees() float32 {
+	return float32(a) * 360 / 256
+}
+
+func (c *Chat) WriteTo(w io.Writer) (int64, error) {
+	s := String(*c)
+	return s.WriteTo(w)
+}
+
+func (c *Chat) ReadFrom(r io.Reader) (int64, error) {
+	var s String
+	n, err := s.ReadFrom(r)
+	*c = Chat(s)
+	return n, err
+}
+
+func (p *Position) WriteTo(w io.Writer) (int64, error) {
+	val := Long((int64(p.X)&0x3FFFFFF)<<38 | (int64(p.Z)&0x3FFFFFF)<<12 | int64(p.Y)&0xFFF)
+	return val.WriteTo(w)
+}
+
+func (p *Position) ReadFrom(r io.Reader) (int64, error) {
+	var val Long
+	n, err := val.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	p.X = int32(val >> 38)
+	p.Y = int32(val << 52 >> 52)
+	p.Z = int32(val << 26 >> 38)
+	return n, nil
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("(%d, %d, %d)", p.X, p.Y, p.Z)
+}
+
+// Array is an array of T prefixed with its length as VarInt.
+type Array[T any] []T
+
+func (a *Array[T]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	l := VarInt(len(*a))
+	if _, err := l.WriteTo(cw); err != nil {
+		return cw.n, err
+	}
+
+	for i := range *a {
+		if err := appendValue(cw, &(*a)[i]); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+func (a *Array[T]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	l := VarInt(0)
+	if _, err := l.ReadFrom(cr); err != nil {
+		return cr.n, err
+	}
+	if l < 0 {
+		return cr.n, fmt.Errorf("negative array length: %d", l)
+	}
+
+	// the length is not trusted for preallocation, a malformed packet runs
+	// out of data long before it could exhaust memory
+	*a = (*a)[:0]
+	for i := 0; i < int(l); i++ {
+		var v T
+		if err := scanValue(cr, &v); err != nil {
+			return cr.n, err
+		}
+		*a = append(*a, v)
+	}
+	return cr.n, nil
+}
+
+// Optional is a value of T prefixed with a Bool telling whether it is present.
+type Optional[T any] struct {
+	Present bool
+	Value   T
+}
+
+func (o *Optional[T]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	present := Bool(o.Present)
+	if _, err := present.WriteTo(cw); err != nil || !o.Present {
+		return cw.n, err
+	}
+
+	err := appendValue(cw, &o.Value)
+	return cw.n, err
+}
+
+func (o *Optional[T]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	var present Bool
+	if _, err := present.ReadFrom(cr); err != nil {
+		return cr.n, err
+	}
+
+	var v T
+	o.Present, o.Value = bool(present), v
+	if !o.Present {
+		return cr.n, nil
+	}
+
+	err := scanValue(cr, &o.Value)
+	return cr.n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
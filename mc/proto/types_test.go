@@ -29,7 +29,7 @@ func TestWriteVarInt(t *testing.T) {
 		t.Run(fmt.Sprintf("VarInt-Write-%d-%x", tt.Input, tt.Want), func(t *testing.T) {
 			buf := bytes.NewBuffer(nil)
 			v := VarInt(tt.Input)
-			_, _ = v.Write(buf)
+			_, _ = v.WriteTo(buf)
 			if !reflect.DeepEqual(tt.Want, buf.Bytes()) {
 				t.Errorf("Want: %v, Got: %v", tt.Want, buf.Bytes())
 				return
@@ -60,7 +60,7 @@ func TestReadVarInt(t *testing.T) {
 		t.Run(fmt.Sprintf("VarInt-Write-%d-%x", tt.Input, tt.Want), func(t *testing.T) {
 			buf := bytes.NewBuffer(tt.Input)
 			var v VarInt
-			_, _ = v.Read(buf)
+			_, _ = v.ReadFrom(buf)
 			if int32(tt.Want) != int32(v) {
 				t.Errorf("Want: %v, Got: %v", tt.Want, v)
 				return
@@ -68,3 +68,96 @@ func TestReadVarInt(t *testing.T) {
 		})
 	}
 }
+
+func TestPosition(t *testing.T) {
+	tests := []struct {
+		Input Position
+		Want  []byte
+	}{
+		{Position{X: 0, Y: 0, Z: 0}, []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{Position{X: 18357644, Y: 831, Z: -20882616}, []byte{0x46, 0x07, 0x63, 0x2c, 0x15, 0xb4, 0x83, 0x3f}},
+		{Position{X: -1, Y: -64, Z: -1}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xc0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Position-%v", tt.Input), func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			_, _ = tt.Input.WriteTo(buf)
+			if !reflect.DeepEqual(tt.Want, buf.Bytes()) {
+				t.Errorf("Want: %x, Got: %x", tt.Want, buf.Bytes())
+				return
+			}
+
+			var got Position
+			if _, err := got.ReadFrom(buf); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.Input {
+				t.Errorf("Want: %v, Got: %v", tt.Input, got)
+			}
+		})
+	}
+}
+
+func TestVarLong(t *testing.T) {
+	tests := []struct {
+		Input int64
+		Want  []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{2147483647, []byte{0xff, 0xff, 0xff, 0xff, 0x07}},
+		{9223372036854775807, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}},
+		{-1, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("VarLong-%d", tt.Input), func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			v := VarLong(tt.Input)
+			_, _ = v.WriteTo(buf)
+			if !reflect.DeepEqual(tt.Want, buf.Bytes()) {
+				t.Errorf("Want: %x, Got: %x", tt.Want, buf.Bytes())
+				return
+			}
+
+			var got VarLong
+			_, _ = got.ReadFrom(buf)
+			if int64(got) != tt.Input {
+				t.Errorf("Want: %v, Got: %v", tt.Input, got)
+			}
+		})
+	}
+}
+
+func TestArrayAndOptional(t *testing.T) {
+	type entry struct {
+		Key   String
+		Value Optional[VarInt]
+	}
+	want := Array[entry]{
+		{Key: "a", Value: Optional[VarInt]{Present: true, Value: 300}},
+		{Key: "b"},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := want.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	if wantBytes := []byte{0x02, 0x01, 'a', 0x01, 0xac, 0x02, 0x01, 'b', 0x00}; !reflect.DeepEqual(wantBytes, buf.Bytes()) {
+		t.Errorf("Want: %x, Got: %x", wantBytes, buf.Bytes())
+	}
+
+	var got Array[entry]
+	n, err := got.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 9 {
+		t.Errorf("Want 9 bytes read, Got: %d", n)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Want: %v, Got: %v", want, got)
+	}
+}
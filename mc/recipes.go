@@ -0,0 +1,249 @@
+package mc
+
+import (
+	"context"
+	"mc-bot/mc/proto"
+	"sort"
+	"sync"
+)
+
+// Recipes holds the recipes sent by the server and the recipe book of the
+// player. It is safe for concurrent use.
+type Recipes struct {
+	mu       sync.RWMutex
+	recipes  map[string]proto.Recipe
+	unlocked map[string]bool
+	settings proto.RecipeBookSettings
+}
+
+func newRecipes() *Recipes {
+	return &Recipes{recipes: map[string]proto.Recipe{}, unlocked: map[string]bool{}}
+}
+
+// Get returns the recipe with given ID, e.g. "minecraft:oak_planks".
+func (r *Recipes) Get(id string) (proto.Recipe, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recipe, ok := r.recipes[id]
+	return recipe, ok
+}
+
+// All returns every known recipe sorted by ID.
+func (r *Recipes) All() []proto.Recipe {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]proto.Recipe, 0, len(r.recipes))
+	for _, recipe := range r.recipes {
+		out = append(out, recipe)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Producing returns the recipes with item itemID as the result.
+func (r *Recipes) Producing(itemID int32) []proto.Recipe {
+	var out []proto.Recipe
+	for _, recipe := range r.All() {
+		if !recipe.Result.Empty() && int32(recipe.Result.ItemID) == itemID {
+			out = append(out, recipe)
+		}
+	}
+	return out
+}
+
+// Unlocked reports whether the recipe is in the recipe book of the player.
+func (r *Recipes) Unlocked(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.unlocked[id]
+}
+
+// Settings returns the recipe book state of each crafting screen.
+func (r *Recipes) Settings() proto.RecipeBookSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.settings
+}
+
+func (c *Client) handleUpdateRecipes(pk proto.Packet) error {
+	var update proto.UpdateRecipesResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	recipes := make(map[string]proto.Recipe, len(update.Recipes))
+	for _, recipe := range update.Recipes {
+		recipes[string(recipe.ID)] = recipe
+	}
+
+	c.Recipes.mu.Lock()
+	defer c.Recipes.mu.Unlock()
+	c.Recipes.recipes = recipes
+	return nil
+}
+
+func (c *Client) handleUpdateRecipeBook(pk proto.Packet) error {
+	var update proto.UpdateRecipeBookResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	book := c.Recipes
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	book.settings = update.Settings
+	switch update.Action {
+	case proto.RecipeBookInit:
+		book.unlocked = make(map[string]bool, len(update.Recipes))
+		fallthrough
+	case proto.RecipeBookAdd:
+		for _, id := range update.Recipes {
+			book.unlocked[string(id)] = true
+		}
+	case proto.RecipeBookRemove:
+		for _, id := range update.Recipes {
+			delete(book.unlocked, string(id))
+		}
+	}
+	return nil
+}
+
+// Craft crafts the recipe count times in the crafting table that is open, or
+// in the 2x2 grid of the player inventory when no container is open. Recipes
+// from the recipe book are placed by the server, others are laid out in the
+// grid item by item. The results are moved to the player inventory.
+func (c *Client) Craft(ctx context.Context, recipeID string, count int) error {
+	recipe, ok := c.Recipes.Get(recipeID)
+	if !ok {
+		return ErrUnknownRecipe
+	}
+
+	window := c.Inventory.Current()
+	size := 2
+	switch {
+	case window.Type == MenuCrafting:
+		size = 3
+	case window.ID != PlayerWindowID:
+		return ErrContainerOpen
+	}
+	if !fitsGrid(recipe, size) {
+		return ErrNotCraftable
+	}
+
+	for i := 0; i < count; i++ {
+		if c.Recipes.Unlocked(recipeID) {
+			if err := c.placeRecipe(window.ID, recipeID); err != nil {
+				return err
+			}
+		} else if err := c.fillGrid(recipe, size); err != nil {
+			return err
+		}
+
+		err := c.waitSlot(ctx, SlotCraftingResult, func(slot proto.Slot) bool { return !slot.Empty() })
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the ack timeout ran out, the server has no result for the grid
+			return ErrMissingIngredients
+		}
+		if err := c.click(SlotCraftingResult, 0, ClickPickup); err != nil {
+			return err
+		}
+		if err := c.stowCarried(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) placeRecipe(windowID int32, recipeID string) error {
+	packet := proto.NewPacket(0x1b)
+	err := packet.Append(&RequestPlaceRecipe{
+		WindowID: proto.Byte(windowID),
+		Recipe:   proto.String(recipeID),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// fillGrid lays out one set of ingredients in the crafting grid of given
+// size, taking them from the player slots of the current window.
+func (c *Client) fillGrid(recipe proto.Recipe, size int) error {
+	for i := 0; i < size*size; i++ {
+		if !c.Inventory.Current().Slots[SlotCraftingInput+i].Empty() {
+			if err := c.ShiftClick(SlotCraftingInput + i); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, ingredient := range recipe.Ingredients {
+		if ingredientEmpty(ingredient) {
+			continue
+		}
+
+		cell := SlotCraftingInput + i
+		if recipe.Type == proto.RecipeShaped {
+			cell = SlotCraftingInput + i/int(recipe.Width)*size + i%int(recipe.Width)
+		}
+
+		source := c.findIngredient(ingredient)
+		if source < 0 {
+			return ErrMissingIngredients
+		}
+		if err := c.click(source, 0, ClickPickup); err != nil {
+			return err
+		}
+		if err := c.click(cell, 1, ClickPickup); err != nil {
+			return err
+		}
+		if err := c.click(source, 0, ClickPickup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findIngredient returns a player slot of the current window with an item
+// matching ingredient or -1.
+func (c *Client) findIngredient(ingredient proto.Ingredient) int {
+	window := c.Inventory.Current()
+	for i := window.playerOffset(); i < len(window.Slots); i++ {
+		slot := window.Slots[i]
+		if slot.Empty() {
+			continue
+		}
+		for _, item := range ingredient {
+			if item.ItemID == slot.ItemID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func fitsGrid(recipe proto.Recipe, size int) bool {
+	switch recipe.Type {
+	case proto.RecipeShaped:
+		return int(recipe.Width) <= size && int(recipe.Height) <= size
+	case proto.RecipeShapeless:
+		return len(recipe.Ingredients) <= size*size
+	}
+	return false
+}
+
+func ingredientEmpty(ingredient proto.Ingredient) bool {
+	for _, item := range ingredient {
+		if !item.Empty() {
+			return false
+		}
+	}
+	return true
+}
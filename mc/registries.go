@@ -0,0 +1,313 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+// Keys of the registries in the registry codec of Login (play), see
+// https://wiki.vg/index.php?title=Registry_Data&oldid=18375
+const (
+	RegistryDimensionType = "minecraft:dimension_type"
+	RegistryBiome         = "minecraft:worldgen/biome"
+	RegistryChatType      = "minecraft:chat_type"
+	RegistryDamageType    = "minecraft:damage_type"
+)
+
+type DimensionType struct {
+	Name               string
+	ID                 int32
+	MinY               int32
+	Height             int32
+	LogicalHeight      int32
+	HasSkylight        bool
+	HasCeiling         bool
+	Ultrawarm          bool
+	Natural            bool
+	CoordinateScale    float64
+	BedWorks           bool
+	RespawnAnchorWorks bool
+	PiglinSafe         bool
+	HasRaids           bool
+	AmbientLight       float32
+	Effects            string
+}
+
+type Biome struct {
+	Name             string
+	ID               int32
+	HasPrecipitation bool
+	Temperature      float32
+	Downfall         float32
+}
+
+// ChatDecoration formats a chat message with translation key TranslationKey
+// filled with Parameters, which are "sender", "target" and "content".
+type ChatDecoration struct {
+	TranslationKey string
+	Parameters     []string
+	Style          map[string]any
+}
+
+type ChatType struct {
+	Name      string
+	ID        int32
+	Chat      ChatDecoration
+	Narration ChatDecoration
+}
+
+type DamageType struct {
+	Name             string
+	ID               int32
+	MessageID        string
+	Scaling          string
+	Exhaustion       float32
+	Effects          string
+	DeathMessageType string
+}
+
+// Registries holds the registries sent by the server in Login (play). It is
+// safe for concurrent use.
+type Registries struct {
+	mu             sync.RWMutex
+	codec          map[string]any
+	dimensionTypes map[string]DimensionType
+	biomes         map[int32]Biome
+	chatTypes      map[int32]ChatType
+	damageTypes    map[int32]DamageType
+}
+
+func newRegistries() *Registries {
+	return &Registries{
+		codec:          map[string]any{},
+		dimensionTypes: map[string]DimensionType{},
+		biomes:         map[int32]Biome{},
+		chatTypes:      map[int32]ChatType{},
+		damageTypes:    map[int32]DamageType{},
+	}
+}
+
+// Codec returns the raw registry codec compound.
+func (r *Registries) Codec() map[string]any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.codec
+}
+
+// DimensionType returns the dimension type with given name, e.g.
+// "minecraft:the_nether".
+func (r *Registries) DimensionType(name string) (DimensionType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dimension, ok := r.dimensionTypes[name]
+	return dimension, ok
+}
+
+func (r *Registries) Biome(id int32) (Biome, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	biome, ok := r.biomes[id]
+	return biome, ok
+}
+
+func (r *Registries) ChatType(id int32) (ChatType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chatType, ok := r.chatTypes[id]
+	return chatType, ok
+}
+
+func (r *Registries) DamageType(id int32) (DamageType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	damageType, ok := r.damageTypes[id]
+	return damageType, ok
+}
+
+// load replaces the registries with the ones in codec.
+func (r *Registries) load(codec map[string]any) {
+	dimensionTypes := map[string]DimensionType{}
+	for _, e := range registryEntries(codec, RegistryDimensionType) {
+		dimensionTypes[e.name] = DimensionType{
+			Name:               e.name,
+			ID:                 e.id,
+			MinY:               int32(nbtInt(e.element["min_y"])),
+			Height:             int32(nbtInt(e.element["height"])),
+			LogicalHeight:      int32(nbtInt(e.element["logical_height"])),
+			HasSkylight:        nbtInt(e.element["has_skylight"]) != 0,
+			HasCeiling:         nbtInt(e.element["has_ceiling"]) != 0,
+			Ultrawarm:          nbtInt(e.element["ultrawarm"]) != 0,
+			Natural:            nbtInt(e.element["natural"]) != 0,
+			CoordinateScale:    nbtFloat(e.element["coordinate_scale"]),
+			BedWorks:           nbtInt(e.element["bed_works"]) != 0,
+			RespawnAnchorWorks: nbtInt(e.element["respawn_anchor_works"]) != 0,
+			PiglinSafe:         nbtInt(e.element["piglin_safe"]) != 0,
+			HasRaids:           nbtInt(e.element["has_raids"]) != 0,
+			AmbientLight:       float32(nbtFloat(e.element["ambient_light"])),
+			Effects:            nbtString(e.element["effects"]),
+		}
+	}
+
+	biomes := map[int32]Biome{}
+	for _, e := range registryEntries(codec, RegistryBiome) {
+		biomes[e.id] = Biome{
+			Name:             e.name,
+			ID:               e.id,
+			HasPrecipitation: nbtInt(e.element["has_precipitation"]) != 0,
+			Temperature:      float32(nbtFloat(e.element["temperature"])),
+			Downfall:         float32(nbtFloat(e.element["downfall"])),
+		}
+	}
+
+	chatTypes := map[int32]ChatType{}
+	for _, e := range registryEntries(codec, RegistryChatType) {
+		chatTypes[e.id] = ChatType{
+			Name:      e.name,
+			ID:        e.id,
+			Chat:      chatDecoration(e.element["chat"]),
+			Narration: chatDecoration(e.element["narration"]),
+		}
+	}
+
+	damageTypes := map[int32]DamageType{}
+	for _, e := range registryEntries(codec, RegistryDamageType) {
+		damageTypes[e.id] = DamageType{
+			Name:             e.name,
+			ID:               e.id,
+			MessageID:        nbtString(e.element["message_id"]),
+			Scaling:          nbtString(e.element["scaling"]),
+			Exhaustion:       float32(nbtFloat(e.element["exhaustion"])),
+			Effects:          nbtString(e.element["effects"]),
+			DeathMessageType: nbtString(e.element["death_message_type"]),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codec = codec
+	r.dimensionTypes = dimensionTypes
+	r.biomes = biomes
+	r.chatTypes = chatTypes
+	r.damageTypes = damageTypes
+}
+
+type registryEntry struct {
+	name    string
+	id      int32
+	element map[string]any
+}
+
+// registryEntries returns the entries of registry key in codec, which look
+// like {type: key, value: [{name, id, element}, ...]}.
+func registryEntries(codec map[string]any, key string) []registryEntry {
+	registry, _ := codec[key].(map[string]any)
+	values, _ := registry["value"].([]any)
+
+	entries := make([]registryEntry, 0, len(values))
+	for _, v := range values {
+		entry, _ := v.(map[string]any)
+		element, _ := entry["element"].(map[string]any)
+		entries = append(entries, registryEntry{
+			name:    nbtString(entry["name"]),
+			id:      int32(nbtInt(entry["id"])),
+			element: element,
+		})
+	}
+	return entries
+}
+
+func chatDecoration(v any) ChatDecoration {
+	compound, _ := v.(map[string]any)
+	decoration := ChatDecoration{TranslationKey: nbtString(compound["translation_key"])}
+	decoration.Style, _ = compound["style"].(map[string]any)
+
+	parameters, _ := compound["parameters"].([]any)
+	for _, p := range parameters {
+		decoration.Parameters = append(decoration.Parameters, nbtString(p))
+	}
+	return decoration
+}
+
+// nbtInt converts any NBT integer to int64, other values to 0.
+func nbtInt(v any) int64 {
+	switch v := v.(type) {
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+// nbtFloat converts any NBT number to float64, other values to 0.
+func nbtFloat(v any) float64 {
+	switch v := v.(type) {
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return float64(nbtInt(v))
+}
+
+func nbtString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// setDimension switches the world to dimension name of type typeName,
+// dropping all chunks and entities of the previous one.
+func (c *Client) setDimension(name, typeName string) {
+	dimension, ok := c.Registries.DimensionType(typeName)
+	if !ok {
+		// keep the overworld bounds for servers sending no codec
+		dimension = DimensionType{Name: typeName, MinY: -64, Height: 384}
+	}
+
+	c.World.reset(dimension.MinY, dimension.Height)
+	c.Player.update(func(state *PlayerState) {
+		state.Dimension = name
+		state.DimensionType = typeName
+	})
+	c.emit(DimensionChangedEvent{Dimension: name, Type: dimension})
+}
+
+// Bits of RespawnResponse.DataKept.
+const (
+	RespawnKeepAttributes = 0x01
+	RespawnKeepMetadata   = 0x02
+)
+
+func (c *Client) handleRespawn(pk proto.Packet) error {
+	var respawn proto.RespawnResponse
+	if err := pk.Scan(&respawn); err != nil {
+		return err
+	}
+
+	id := c.Player.EntityID()
+	own, _ := c.Entities.Get(id)
+	player := &Entity{ID: id, Type: EntityTypePlayer}
+	if respawn.DataKept&RespawnKeepAttributes != 0 {
+		player.Attributes = own.Attributes
+	}
+	if respawn.DataKept&RespawnKeepMetadata != 0 {
+		player.Metadata = own.Metadata
+	}
+	c.Entities.clear()
+	c.Entities.add(player)
+	c.death.reset()
+
+	c.Player.update(func(state *PlayerState) {
+		state.GameMode = byte(respawn.GameMode)
+	})
+	c.setDimension(string(respawn.DimensionName), string(respawn.DimensionType))
+	return nil
+}
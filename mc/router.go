@@ -0,0 +1,373 @@
+package mc
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mc-bot/mc/proto"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type ArgType byte
+
+const (
+	ArgWord  ArgType = iota // ArgWord is a single word or a "quoted phrase"
+	ArgInt                  // ArgInt is an integer
+	ArgFloat                // ArgFloat is a number
+	ArgBool                 // ArgBool is true/false, yes/no or on/off
+	ArgRest                 // ArgRest is the rest of the message, it has to be the last argument
+)
+
+type ArgSpec struct {
+	Name     string
+	Type     ArgType
+	Optional bool // Optional arguments have to follow the required ones
+}
+
+// BotCommand is a command players run by chatting, e.g. "!follow Steve".
+type BotCommand struct {
+	Name       string
+	Aliases    []string
+	Args       []ArgSpec
+	Help       string
+	Restricted bool // Restricted commands may only be run by allowed players
+	Handler    func(cmd *CommandContext) error
+}
+
+// Usage returns the syntax of the command, e.g. "!follow <player> [distance]".
+func (b *BotCommand) Usage(prefix string) string {
+	parts := []string{prefix + b.Name}
+	for _, arg := range b.Args {
+		if arg.Optional {
+			parts = append(parts, "["+arg.Name+"]")
+		} else {
+			parts = append(parts, "<"+arg.Name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ReplyMode selects how CommandContext.Reply answers.
+type ReplyMode byte
+
+const (
+	ReplySame    ReplyMode = iota // ReplySame whispers to whispered commands and answers others in public chat
+	ReplyWhisper                  // ReplyWhisper always whispers to the sender
+	ReplyPublic                   // ReplyPublic always answers in public chat
+)
+
+// chatTypeWhisper is the chat type of whispers received from other players.
+const chatTypeWhisper = "minecraft:msg_command_incoming"
+
+// CommandContext is passed to command handlers.
+type CommandContext struct {
+	Client     *Client
+	Command    *BotCommand
+	Sender     proto.Uuid
+	SenderName string
+	Whisper    bool           // Whisper is set when the command was sent with /msg
+	Args       map[string]any // Args holds the parsed values of present arguments
+	router     *CommandRouter
+}
+
+// String returns a word or rest argument, or "" when it was not given.
+func (cmd *CommandContext) String(name string) string {
+	v, _ := cmd.Args[name].(string)
+	return v
+}
+
+// Int returns an integer argument, or 0 when it was not given.
+func (cmd *CommandContext) Int(name string) int {
+	v, _ := cmd.Args[name].(int)
+	return v
+}
+
+// Float returns a number argument, or 0 when it was not given.
+func (cmd *CommandContext) Float(name string) float64 {
+	v, _ := cmd.Args[name].(float64)
+	return v
+}
+
+// Bool returns a boolean argument, or false when it was not given.
+func (cmd *CommandContext) Bool(name string) bool {
+	v, _ := cmd.Args[name].(bool)
+	return v
+}
+
+// Has reports whether an optional argument was given.
+func (cmd *CommandContext) Has(name string) bool {
+	_, ok := cmd.Args[name]
+	return ok
+}
+
+// Reply answers the sender according to the router ReplyMode. Public replies
+// are always sent as chat, so echoed input like "/op" never runs a command.
+func (cmd *CommandContext) Reply(format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	mode := cmd.router.Reply
+	if mode == ReplyWhisper || (mode == ReplySame && cmd.Whisper) {
+		return cmd.Client.SendCommand(truncate("msg "+cmd.SenderName+" "+message, maxChatLength))
+	}
+	return cmd.Client.sendChatMessage(truncate(message, maxChatLength))
+}
+
+// truncate cuts s to at most n characters as counted by textLength,
+// without splitting a character.
+func truncate(s string, n int) string {
+	length := 0
+	for i, r := range s {
+		size := 1
+		if r >= 0x10000 {
+			size = 2
+		}
+		if length+size > n {
+			return s[:i]
+		}
+		length += size
+	}
+	return s
+}
+
+// CommandRouter runs bot commands sent in chat. It is safe for concurrent
+// use.
+type CommandRouter struct {
+	Prefix string    // Prefix starts commands, e.g. "!"
+	Reply  ReplyMode // Reply selects how handlers answer
+
+	mu       sync.RWMutex
+	commands map[string]*BotCommand
+	allowed  map[proto.Uuid]bool
+}
+
+func NewCommandRouter(prefix string) *CommandRouter {
+	r := &CommandRouter{
+		Prefix:   prefix,
+		commands: map[string]*BotCommand{},
+		allowed:  map[proto.Uuid]bool{},
+	}
+	r.Handle(BotCommand{
+		Name:    "help",
+		Args:    []ArgSpec{{Name: "command", Optional: true}},
+		Help:    "lists commands or shows the usage of one",
+		Handler: r.help,
+	})
+	return r
+}
+
+// Handle registers a command, replacing one with the same name or alias.
+func (r *CommandRouter) Handle(cmd BotCommand) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		r.commands[strings.ToLower(name)] = &cmd
+	}
+}
+
+// Allow lets players run restricted commands.
+func (r *CommandRouter) Allow(players ...proto.Uuid) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, player := range players {
+		r.allowed[player] = true
+	}
+}
+
+// Disallow takes back Allow.
+func (r *CommandRouter) Disallow(players ...proto.Uuid) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, player := range players {
+		delete(r.allowed, player)
+	}
+}
+
+func (r *CommandRouter) Allowed(player proto.Uuid) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.allowed[player]
+}
+
+func (r *CommandRouter) command(name string) (*BotCommand, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmd, ok := r.commands[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Attach runs commands from the chat of c until detach is called. Handlers
+// run in their own goroutines and errors they return are sent as replies.
+func (r *CommandRouter) Attach(c *Client) (detach func()) {
+	return c.Subscribe(func(event Event) {
+		chat, ok := event.(ChatEvent)
+		if !ok || chat.Kind != ChatPlayer {
+			return
+		}
+		if own, err := parseUUID(c.Player.UUID()); err == nil && own == chat.Sender {
+			return
+		}
+
+		text := strings.TrimSpace(chat.Message)
+		if !strings.HasPrefix(text, r.Prefix) {
+			return
+		}
+
+		cmd := &CommandContext{
+			Client:     c,
+			Sender:     chat.Sender,
+			SenderName: chat.SenderName.PlainText(),
+			Whisper:    chat.ChatType.Name == chatTypeWhisper,
+			router:     r,
+		}
+		// the sender name may be decorated by plugins, whispers need the real one
+		if player, ok := c.Players.Get(chat.Sender); ok {
+			cmd.SenderName = player.Name
+		}
+		go r.run(cmd, strings.TrimPrefix(text, r.Prefix))
+	})
+}
+
+func (r *CommandRouter) run(cmd *CommandContext, line string) {
+	err := r.dispatch(cmd, line)
+	if err == nil || errors.Is(err, errUnknownBotCommand) {
+		return
+	}
+	if err := cmd.Reply("%s", err); err != nil {
+		log.Printf("[WARN] cannot reply to %s: %v", cmd.SenderName, err)
+	}
+}
+
+var errUnknownBotCommand = errors.New("unknown command")
+
+func (r *CommandRouter) dispatch(cmd *CommandContext, line string) error {
+	words, err := splitArgs(line)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return errUnknownBotCommand
+	}
+
+	command, ok := r.command(words[0].text)
+	if !ok {
+		return errUnknownBotCommand
+	}
+	if command.Restricted && !r.Allowed(cmd.Sender) {
+		return errors.New("you are not allowed to use this command")
+	}
+
+	cmd.Command = command
+	if cmd.Args, err = parseArgs(command.Args, line, words[1:]); err != nil {
+		return fmt.Errorf("%v, usage: %s", err, command.Usage(r.Prefix))
+	}
+	return command.Handler(cmd)
+}
+
+type word struct {
+	text  string
+	start int // start is the index of the word in the line
+}
+
+// splitArgs splits line into words, keeping "quoted phrases" together.
+func splitArgs(line string) ([]word, error) {
+	var words []word
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+
+		if line[i] == '"' {
+			end := strings.IndexByte(line[i+1:], '"')
+			if end < 0 {
+				return nil, errors.New("unclosed quote")
+			}
+			words = append(words, word{line[i+1 : i+1+end], i})
+			i += end + 2
+			continue
+		}
+
+		end := strings.IndexByte(line[i:], ' ')
+		if end < 0 {
+			end = len(line) - i
+		}
+		words = append(words, word{line[i : i+end], i})
+		i += end
+	}
+	return words, nil
+}
+
+func parseArgs(specs []ArgSpec, line string, words []word) (map[string]any, error) {
+	args := map[string]any{}
+	for i, spec := range specs {
+		if i >= len(words) {
+			if !spec.Optional {
+				return nil, fmt.Errorf("missing %s", spec.Name)
+			}
+			continue
+		}
+
+		w := words[i].text
+		switch spec.Type {
+		case ArgWord:
+			args[spec.Name] = w
+		case ArgRest:
+			args[spec.Name] = strings.TrimSpace(line[words[i].start:])
+			return args, nil
+		case ArgInt:
+			v, err := strconv.Atoi(w)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an integer", spec.Name)
+			}
+			args[spec.Name] = v
+		case ArgFloat:
+			v, err := strconv.ParseFloat(w, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", spec.Name)
+			}
+			args[spec.Name] = v
+		case ArgBool:
+			switch strings.ToLower(w) {
+			case "true", "yes", "on":
+				args[spec.Name] = true
+			case "false", "no", "off":
+				args[spec.Name] = false
+			default:
+				return nil, fmt.Errorf("%s must be true or false", spec.Name)
+			}
+		}
+	}
+
+	if len(words) > len(specs) {
+		return nil, errors.New("too many arguments")
+	}
+	return args, nil
+}
+
+func (r *CommandRouter) help(cmd *CommandContext) error {
+	if name := cmd.String("command"); name != "" {
+		command, ok := r.command(name)
+		if !ok {
+			return fmt.Errorf("unknown command %s", name)
+		}
+		return cmd.Reply("%s - %s", command.Usage(r.Prefix), command.Help)
+	}
+
+	r.mu.RLock()
+	var names []string
+	for name, command := range r.commands {
+		if name == strings.ToLower(command.Name) {
+			names = append(names, r.Prefix+command.Name)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	return cmd.Reply("commands: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,154 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  []word
+		Err   bool
+	}{
+		{"", nil, false},
+		{"   ", nil, false},
+		{"goto", []word{{"goto", 0}}, false},
+		{"goto 1 2  3", []word{{"goto", 0}, {"1", 5}, {"2", 7}, {"3", 10}}, false},
+		{` say "hello world" now`, []word{{"say", 1}, {"hello world", 5}, {"now", 19}}, false},
+		{`say ""`, []word{{"say", 0}, {"", 4}}, false},
+		{`say "unclosed`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Input, func(t *testing.T) {
+			got, err := splitArgs(tt.Input)
+			if (err != nil) != tt.Err {
+				t.Fatalf("Want error: %v, Got: %v", tt.Err, err)
+			}
+			if !reflect.DeepEqual(tt.Want, got) {
+				t.Errorf("Want: %#v, Got: %#v", tt.Want, got)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	specs := []ArgSpec{
+		{Name: "x", Type: ArgInt},
+		{Name: "speed", Type: ArgFloat, Optional: true},
+		{Name: "sprint", Type: ArgBool, Optional: true},
+	}
+	rest := []ArgSpec{
+		{Name: "player", Type: ArgWord},
+		{Name: "message", Type: ArgRest},
+	}
+
+	tests := []struct {
+		Name  string
+		Specs []ArgSpec
+		Input string
+		Want  map[string]any
+		Err   bool
+	}{
+		{"all", specs, "10 1.5 yes", map[string]any{"x": 10, "speed": 1.5, "sprint": true}, false},
+		{"optional", specs, "-3", map[string]any{"x": -3}, false},
+		{"bool off", specs, "0 1 OFF", map[string]any{"x": 0, "speed": 1.0, "sprint": false}, false},
+		{"missing", specs, "", nil, true},
+		{"bad int", specs, "ten", nil, true},
+		{"bad float", specs, "1 fast", nil, true},
+		{"bad bool", specs, "1 1 maybe", nil, true},
+		{"too many", specs, "1 2 true 4", nil, true},
+		{"rest", rest, `bob hi  "there" you `, map[string]any{"player": "bob", "message": `hi  "there" you`}, false},
+		{"empty rest", rest, "bob", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			words, err := splitArgs(tt.Input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseArgs(tt.Specs, tt.Input, words)
+			if (err != nil) != tt.Err {
+				t.Fatalf("Want error: %v, Got: %v", tt.Err, err)
+			}
+			if !tt.Err && !reflect.DeepEqual(tt.Want, got) {
+				t.Errorf("Want: %#v, Got: %#v", tt.Want, got)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		Input string
+		N     int
+		Want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "hé"},
+		{"日本語", 2, "日本"},
+		{"a😀b", 2, "a"},
+		{"a😀b", 3, "a😀"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.Input, tt.N); got != tt.Want {
+			t.Errorf("truncate(%q, %d): Want: %q, Got: %q", tt.Input, tt.N, tt.Want, got)
+		}
+	}
+}
+
+// testConn returns a client connected to a local listener and the server
+// side of the connection.
+func testConn(t *testing.T) (*Client, net.Conn) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	conn, err := net.DialTCP("tcp", nil, listener.Addr().(*net.TCPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Close()
+	})
+
+	client := NewClient(Version1_20_1)
+	client.Conn = conn
+	return &client, server
+}
+
+func TestReplyEchoesCommandsAsChat(t *testing.T) {
+	client, server := testConn(t)
+	cmd := &CommandContext{Client: client, SenderName: "Bob", router: &CommandRouter{Reply: ReplyPublic}}
+
+	if err := cmd.Reply("%s", "/op Bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	pk := proto.NewPacketFromReader(server)
+	if pk.ID != 0x05 {
+		t.Fatalf("Want chat message packet 0x05, Got: %#x", pk.ID)
+	}
+	var message proto.String
+	if err := pk.Scan(&message); err != nil {
+		t.Fatal(err)
+	}
+	if message != "/op Bob" {
+		t.Errorf("Want: %q, Got: %q", "/op Bob", message)
+	}
+}
@@ -0,0 +1,408 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Scoreboard display slots. Slots DisplaySidebarTeam+color show the sidebar
+// to members of teams with that color.
+const (
+	DisplayList        = 0
+	DisplaySidebar     = 1
+	DisplayBelowName   = 2
+	DisplaySidebarTeam = 3
+)
+
+// Objective types.
+const (
+	ObjectiveInteger = 0
+	ObjectiveHearts  = 1
+)
+
+// sidebarLines is how many scores the sidebar shows.
+const sidebarLines = 15
+
+type Objective struct {
+	Name        string
+	DisplayName proto.Component
+	Type        int32
+	Scores      map[string]int32 // Scores maps entries, i.e. player names or entity UUIDs, to their score
+}
+
+// Bits of Team.FriendlyFlags.
+const (
+	TeamFriendlyFire = 0x01
+	TeamSeeInvisible = 0x02
+)
+
+type Team struct {
+	Name              string
+	DisplayName       proto.Component
+	Prefix            proto.Component
+	Suffix            proto.Component
+	Color             int32 // Color is a formatting code from 0 (black) to 15 (white), -1 for none
+	FriendlyFlags     byte
+	NameTagVisibility string
+	CollisionRule     string
+	Members           map[string]bool
+}
+
+// ScoreLine is a line of the sidebar.
+type ScoreLine struct {
+	Entry string
+	Value int32
+	Text  string // Text is the entry decorated with the prefix and suffix of its team, without formatting codes
+}
+
+// ObjectiveChangedEvent is emitted when an objective is created, changed,
+// removed or moved to another display slot.
+type ObjectiveChangedEvent struct {
+	Name    string
+	Removed bool
+}
+
+// ScoreChangedEvent is emitted when a score changes. Objective is empty
+// when the scores of Entry were reset for all objectives.
+type ScoreChangedEvent struct {
+	Entry     string
+	Objective string
+	Value     int32
+	Removed   bool
+}
+
+// TeamChangedEvent is emitted when a team or its members change.
+type TeamChangedEvent struct {
+	Name    string
+	Removed bool
+}
+
+// Scoreboard tracks objectives, scores and teams. It is safe for
+// concurrent use.
+type Scoreboard struct {
+	mu         sync.RWMutex
+	objectives map[string]*Objective
+	display    map[int]string // display maps display slots to objective names
+	teams      map[string]*Team
+}
+
+func newScoreboard() *Scoreboard {
+	return &Scoreboard{
+		objectives: map[string]*Objective{},
+		display:    map[int]string{},
+		teams:      map[string]*Team{},
+	}
+}
+
+func (o *Objective) clone() Objective {
+	out := *o
+	out.Scores = make(map[string]int32, len(o.Scores))
+	for k, v := range o.Scores {
+		out.Scores[k] = v
+	}
+	return out
+}
+
+func (t *Team) clone() Team {
+	out := *t
+	out.Members = make(map[string]bool, len(t.Members))
+	for k := range t.Members {
+		out.Members[k] = true
+	}
+	return out
+}
+
+func (s *Scoreboard) Objective(name string) (Objective, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objective, ok := s.objectives[name]
+	if !ok {
+		return Objective{}, false
+	}
+	return objective.clone(), true
+}
+
+// Objectives returns all objectives sorted by name.
+func (s *Scoreboard) Objectives() []Objective {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Objective, 0, len(s.objectives))
+	for _, objective := range s.objectives {
+		out = append(out, objective.clone())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Displayed returns the objective shown in a display slot.
+func (s *Scoreboard) Displayed(slot int) (Objective, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objective, ok := s.objectives[s.display[slot]]
+	if !ok {
+		return Objective{}, false
+	}
+	return objective.clone(), true
+}
+
+// Score returns the score of entry in objective.
+func (s *Scoreboard) Score(entry, objective string) (int32, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.objectives[objective]
+	if !ok {
+		return 0, false
+	}
+	value, ok := o.Scores[entry]
+	return value, ok
+}
+
+func (s *Scoreboard) Team(name string) (Team, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	team, ok := s.teams[name]
+	if !ok {
+		return Team{}, false
+	}
+	return team.clone(), true
+}
+
+// Teams returns all teams sorted by name.
+func (s *Scoreboard) Teams() []Team {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Team, 0, len(s.teams))
+	for _, team := range s.teams {
+		out = append(out, team.clone())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// TeamOf returns the team of entry.
+func (s *Scoreboard) TeamOf(entry string) (Team, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	team := s.teamOf(entry)
+	if team == nil {
+		return Team{}, false
+	}
+	return team.clone(), true
+}
+
+// teamOf returns the team of entry or nil. Caller must hold the lock.
+func (s *Scoreboard) teamOf(entry string) *Team {
+	for _, team := range s.teams {
+		if team.Members[entry] {
+			return team
+		}
+	}
+	return nil
+}
+
+// Sidebar returns the sidebar objective as seen by player and its lines
+// in the order the game shows them: highest score first, at most 15.
+func (s *Scoreboard) Sidebar(player string) (Objective, []ScoreLine, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name, ok := "", false
+	if team := s.teamOf(player); team != nil && team.Color >= 0 {
+		name, ok = s.display[DisplaySidebarTeam+int(team.Color)]
+	}
+	if !ok {
+		name = s.display[DisplaySidebar]
+	}
+	objective, ok := s.objectives[name]
+	if !ok {
+		return Objective{}, nil, false
+	}
+
+	lines := make([]ScoreLine, 0, len(objective.Scores))
+	for entry, value := range objective.Scores {
+		text := entry
+		if team := s.teamOf(entry); team != nil {
+			text = team.Prefix.String() + entry + team.Suffix.String()
+		}
+		line := ScoreLine{Entry: entry, Value: value, Text: stripFormatting(text)}
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Value != lines[j].Value {
+			return lines[i].Value > lines[j].Value
+		}
+		return lines[i].Entry < lines[j].Entry
+	})
+	if len(lines) > sidebarLines {
+		lines = lines[:sidebarLines]
+	}
+	return objective.clone(), lines, true
+}
+
+func (c *Client) handleDisplayObjective(pk proto.Packet) error {
+	var display proto.DisplayObjectiveResponse
+	if err := pk.Scan(&display); err != nil {
+		return err
+	}
+
+	s := c.Scoreboard
+	s.mu.Lock()
+	if display.ScoreName == "" {
+		delete(s.display, int(display.Position))
+	} else {
+		s.display[int(display.Position)] = string(display.ScoreName)
+	}
+	s.mu.Unlock()
+
+	c.emit(ObjectiveChangedEvent{Name: string(display.ScoreName)})
+	return nil
+}
+
+func (c *Client) handleUpdateObjectives(pk proto.Packet) error {
+	var update proto.UpdateObjectivesResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	name := string(update.Name)
+	displayName, _ := update.DisplayName.Component()
+
+	s := c.Scoreboard
+	s.mu.Lock()
+	switch update.Mode {
+	case proto.ObjectiveCreate:
+		s.objectives[name] = &Objective{
+			Name:        name,
+			DisplayName: displayName,
+			Type:        int32(update.Type),
+			Scores:      map[string]int32{},
+		}
+	case proto.ObjectiveRemove:
+		delete(s.objectives, name)
+		for slot, displayed := range s.display {
+			if displayed == name {
+				delete(s.display, slot)
+			}
+		}
+	case proto.ObjectiveUpdate:
+		if objective, ok := s.objectives[name]; ok {
+			objective.DisplayName = displayName
+			objective.Type = int32(update.Type)
+		}
+	}
+	s.mu.Unlock()
+
+	c.emit(ObjectiveChangedEvent{Name: name, Removed: update.Mode == proto.ObjectiveRemove})
+	return nil
+}
+
+func (c *Client) handleUpdateScore(pk proto.Packet) error {
+	var update proto.UpdateScoreResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	entry, name := string(update.Entity), string(update.Objective)
+	removed := update.Action == proto.ScoreRemove
+
+	s := c.Scoreboard
+	s.mu.Lock()
+	switch {
+	case removed && name == "":
+		// 1.20.1 has no Reset Score packet, an empty objective resets all
+		for _, objective := range s.objectives {
+			delete(objective.Scores, entry)
+		}
+	case removed:
+		if objective, ok := s.objectives[name]; ok {
+			delete(objective.Scores, entry)
+		}
+	default:
+		if objective, ok := s.objectives[name]; ok {
+			objective.Scores[entry] = int32(update.Value)
+		}
+	}
+	s.mu.Unlock()
+
+	c.emit(ScoreChangedEvent{Entry: entry, Objective: name, Value: int32(update.Value), Removed: removed})
+	return nil
+}
+
+func (c *Client) handleUpdateTeams(pk proto.Packet) error {
+	var update proto.UpdateTeamsResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	name := string(update.Name)
+	s := c.Scoreboard
+	s.mu.Lock()
+	team, ok := s.teams[name]
+	switch update.Mode {
+	case proto.TeamCreate:
+		team = &Team{Name: name, Members: map[string]bool{}}
+		s.teams[name] = team
+		team.update(update.Info)
+		ok = true
+	case proto.TeamRemove:
+		delete(s.teams, name)
+	case proto.TeamUpdate:
+		if ok {
+			team.update(update.Info)
+		}
+	}
+	if ok {
+		for _, entity := range update.Entities {
+			switch update.Mode {
+			case proto.TeamCreate, proto.TeamAddEntities:
+				// an entry is in one team at most
+				if other := s.teamOf(string(entity)); other != nil {
+					delete(other.Members, string(entity))
+				}
+				team.Members[string(entity)] = true
+			case proto.TeamRemoveEntities:
+				delete(team.Members, string(entity))
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	c.emit(TeamChangedEvent{Name: name, Removed: update.Mode == proto.TeamRemove})
+	return nil
+}
+
+func (t *Team) update(info proto.TeamInfo) {
+	t.DisplayName, _ = info.DisplayName.Component()
+	t.Prefix, _ = info.Prefix.Component()
+	t.Suffix, _ = info.Suffix.Component()
+	t.FriendlyFlags = byte(info.FriendlyFlags)
+	t.NameTagVisibility = string(info.NameTagVisibility)
+	t.CollisionRule = string(info.CollisionRule)
+	t.Color = int32(info.Color)
+	if info.Color < 0 || info.Color > 15 {
+		t.Color = -1
+	}
+}
+
+// stripFormatting removes legacy formatting codes like "§c", which plugins
+// use in entries to make sidebar lines unique.
+func stripFormatting(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "§") {
+			i += len("§")
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,331 @@
+package mc
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"mc-bot/mc/proto"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrKeysExpired = errors.New("profile keys expired")
+
+// ProfileKeys is the key pair Mojang issues for signing chat, see
+// https://wiki.vg/index.php?title=Mojang_API&oldid=18375#Player_Certificates
+type ProfileKeys struct {
+	PrivateKey *rsa.PrivateKey
+	PublicKey  []byte // PublicKey is the DER encoded X.509 public key
+	Signature  []byte // Signature is the Mojang signature of the public key
+	ExpiresAt  time.Time
+}
+
+// KeySource provides profile keys when joining a server.
+type KeySource interface {
+	ProfileKeys() (*ProfileKeys, error)
+}
+
+// ProfileKeys lets already loaded keys be used as a KeySource.
+func (k *ProfileKeys) ProfileKeys() (*ProfileKeys, error) {
+	return k, nil
+}
+
+// KeyFile is a KeySource reading keys with LoadProfileKeys on every login, so
+// the file can be refreshed while the client runs.
+type KeyFile string
+
+func (f KeyFile) ProfileKeys() (*ProfileKeys, error) {
+	return LoadProfileKeys(string(f))
+}
+
+// LoadProfileKeys reads keys saved from the player certificates endpoint of
+// the Mojang API.
+func LoadProfileKeys(path string) (*ProfileKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfileKeys(data)
+}
+
+// ParseProfileKeys parses the JSON response of the player certificates
+// endpoint of the Mojang API.
+func ParseProfileKeys(data []byte) (*ProfileKeys, error) {
+	var certificates struct {
+		KeyPair struct {
+			PrivateKey string `json:"privateKey"`
+			PublicKey  string `json:"publicKey"`
+		} `json:"keyPair"`
+		PublicKeySignature string    `json:"publicKeySignatureV2"`
+		ExpiresAt          time.Time `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(data, &certificates); err != nil {
+		return nil, fmt.Errorf("cannot decode profile keys: %w", err)
+	}
+
+	private, _ := pem.Decode([]byte(certificates.KeyPair.PrivateKey))
+	public, _ := pem.Decode([]byte(certificates.KeyPair.PublicKey))
+	if private == nil || public == nil {
+		return nil, errors.New("profile keys are not PEM encoded")
+	}
+
+	// the PEM headers say RSA but the keys are PKCS#8 and X.509
+	key, err := x509.ParsePKCS8PrivateKey(private.Bytes)
+	if err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(private.Bytes); err != nil {
+			return nil, fmt.Errorf("cannot parse private key: %w", err)
+		}
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(certificates.PublicKeySignature)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode public key signature: %w", err)
+	}
+
+	return &ProfileKeys{
+		PrivateKey: rsaKey,
+		PublicKey:  public.Bytes,
+		Signature:  signature,
+		ExpiresAt:  certificates.ExpiresAt,
+	}, nil
+}
+
+// chatSigner signs messages of the chat session started after joining.
+type chatSigner struct {
+	mu        sync.Mutex
+	keys      *ProfileKeys // keys is nil when chat is unsigned
+	sessionID proto.Uuid
+	index     int32
+}
+
+func (s *chatSigner) active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys != nil
+}
+
+// sign returns the signature of a message sent by sender or nil when chat is
+// unsigned. Every call advances the message chain.
+func (s *chatSigner) sign(sender proto.Uuid, salt int64, timestamp time.Time, message string, lastSeen []proto.Signature) (*proto.Signature, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys == nil {
+		return nil, nil
+	}
+
+	h := sha256.New()
+	writeSignedMessage(h, sender, s.sessionID, s.index, salt, timestamp, message, lastSeen)
+	s.index++
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.keys.PrivateKey, crypto.SHA256, h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	var out proto.Signature
+	copy(out[:], sig)
+	return &out, nil
+}
+
+// writeSignedMessage writes the data covered by a message signature, which
+// is the vanilla PlayerChatMessage.updateSignature.
+func writeSignedMessage(h hash.Hash, sender, session proto.Uuid, index int32, salt int64, timestamp time.Time, message string, lastSeen []proto.Signature) {
+	_ = binary.Write(h, binary.BigEndian, int32(1))
+	h.Write(uuidBytes(sender))
+	h.Write(uuidBytes(session))
+	_ = binary.Write(h, binary.BigEndian, index)
+
+	_ = binary.Write(h, binary.BigEndian, salt)
+	_ = binary.Write(h, binary.BigEndian, timestamp.Unix())
+	_ = binary.Write(h, binary.BigEndian, int32(len(message)))
+	h.Write([]byte(message))
+	_ = binary.Write(h, binary.BigEndian, int32(len(lastSeen)))
+	for _, sig := range lastSeen {
+		h.Write(sig[:])
+	}
+}
+
+func uuidBytes(u proto.Uuid) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, 16))
+	_, _ = u.WriteTo(buf)
+	return buf.Bytes()
+}
+
+func randomUUID() proto.Uuid {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	buf[6] = buf[6]&0x0f | 0x40 // version 4
+	buf[8] = buf[8]&0x3f | 0x80 // variant 10
+	return *proto.NewUuidFromBytes(buf[:])
+}
+
+// startChatSession sends the profile keys to the server so that it accepts
+// signed messages. It does nothing when Client.Keys is nil.
+func (c *Client) startChatSession() error {
+	if c.Keys == nil {
+		return nil
+	}
+
+	keys, err := c.Keys.ProfileKeys()
+	if err != nil {
+		return fmt.Errorf("cannot load profile keys: %w", err)
+	}
+	if time.Now().After(keys.ExpiresAt) {
+		return ErrKeysExpired
+	}
+
+	sessionID := randomUUID()
+	packet := proto.NewPacket(0x06)
+	err = packet.Append(&RequestPlayerSession{
+		SessionID:          sessionID,
+		PublicKeyExpiresAt: proto.Long(keys.ExpiresAt.UnixMilli()),
+		PublicKey:          keys.PublicKey,
+		PublicKeySignature: keys.Signature,
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.SendPacket(packet); err != nil {
+		return err
+	}
+
+	c.signer.mu.Lock()
+	defer c.signer.mu.Unlock()
+	c.signer.keys, c.signer.sessionID, c.signer.index = keys, sessionID, 0
+	return nil
+}
+
+// remoteSession is the chat session of another player used to verify their
+// messages.
+type remoteSession struct {
+	id        proto.Uuid
+	key       *rsa.PublicKey
+	expiresAt time.Time
+}
+
+// chatSessions holds the chat sessions of other players announced in Player
+// Info Update. It is safe for concurrent use.
+type chatSessions struct {
+	mu       sync.RWMutex
+	sessions map[proto.Uuid]remoteSession
+}
+
+func (s *chatSessions) set(player proto.Uuid, session proto.ChatSession) error {
+	key, err := x509.ParsePKIXPublicKey(session.PublicKey)
+	if err != nil {
+		return fmt.Errorf("cannot parse chat session key of %s: %w", player.String(), err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("chat session key of %s is not an RSA key", player.String())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = map[proto.Uuid]remoteSession{}
+	}
+	s.sessions[player] = remoteSession{
+		id:        session.SessionID,
+		key:       rsaKey,
+		expiresAt: time.UnixMilli(int64(session.PublicKeyExpiresAt)),
+	}
+	return nil
+}
+
+func (s *chatSessions) remove(player proto.Uuid) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, player)
+}
+
+func (s *chatSessions) get(player proto.Uuid) (remoteSession, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[player]
+	return session, ok
+}
+
+// verify reports whether a signed player message matches the chat session
+// of its sender. The session keys themselves are not checked against the
+// Mojang signature.
+func (c *Client) verify(chat proto.PlayerChatResponse, lastSeen []proto.Signature) bool {
+	session, ok := c.sessions.get(chat.Sender)
+	if !ok || !chat.Signature.Present || time.Now().After(session.expiresAt) {
+		return false
+	}
+
+	h := sha256.New()
+	timestamp := time.UnixMilli(int64(chat.Timestamp))
+	writeSignedMessage(h, chat.Sender, session.id, int32(chat.Index), int64(chat.Salt), timestamp, string(chat.Message), lastSeen)
+	return rsa.VerifyPKCS1v15(session.key, crypto.SHA256, h.Sum(nil), chat.Signature.Value[:]) == nil
+}
+
+// signatureCacheSize is the size of the vanilla MessageSignatureCache.
+const signatureCacheSize = 128
+
+// signatureCache resolves signatures referenced by index in Player Chat. It
+// mirrors the cache of the server and is only used by the goroutine handling
+// packets.
+type signatureCache struct {
+	entries [signatureCacheSize]*proto.Signature
+}
+
+// unpack resolves previous messages of a Player Chat. ok is false when a
+// referenced signature is not known.
+func (s *signatureCache) unpack(previous []proto.PreviousMessage) (out []proto.Signature, ok bool) {
+	for _, m := range previous {
+		if m.ID == 0 {
+			out = append(out, m.Signature)
+			continue
+		}
+
+		i := int(m.ID) - 1
+		if i < 0 || i >= len(s.entries) || s.entries[i] == nil {
+			return nil, false
+		}
+		out = append(out, *s.entries[i])
+	}
+	return out, true
+}
+
+// push records the signatures seen by a message followed by the message
+// signature, moving older entries back.
+func (s *signatureCache) push(lastSeen []proto.Signature, signature *proto.Signature) {
+	queue := append([]proto.Signature(nil), lastSeen...)
+	if signature != nil {
+		queue = append(queue, *signature)
+	}
+
+	pushed := make(map[proto.Signature]bool, len(queue))
+	for _, sig := range queue {
+		pushed[sig] = true
+	}
+
+	for i := 0; len(queue) > 0 && i < len(s.entries); i++ {
+		old := s.entries[i]
+		last := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		s.entries[i] = &last
+		if old != nil && !pushed[*old] {
+			queue = append([]proto.Signature{*old}, queue...)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package mc
+
+import (
+	"context"
+	"fmt"
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+const (
+	signLines = 4
+	// maxSignLineLength is the longest line Update Sign accepts.
+	maxSignLineLength = 384
+)
+
+// SignText is one side of a sign.
+type SignText struct {
+	Lines   [signLines]proto.Component
+	Color   string // Color is the dye color of the text, e.g. "black"
+	Glowing bool
+}
+
+// Sign is the text of a sign or hanging sign.
+type Sign struct {
+	Front SignText
+	Back  SignText
+	Waxed bool // Waxed signs cannot be edited
+}
+
+// SignEditorOpenedEvent is emitted when the server lets the player edit
+// a side of a sign, e.g. after placing it. See EditSign.
+type SignEditorOpenedEvent struct {
+	Position proto.Position
+	Front    bool
+}
+
+// signEditor is the sign the server opened the editor for.
+type signEditor struct {
+	mu   sync.Mutex
+	open bool
+	pos  proto.Position
+}
+
+func (e *signEditor) set(pos proto.Position) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.open, e.pos = true, pos
+}
+
+// take closes the editor and reports whether it was open for pos.
+func (e *signEditor) take(pos proto.Position) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ok := e.open && e.pos == pos
+	e.open = false
+	return ok
+}
+
+// Sign returns the text of the sign at pos from its block entity.
+func (c *Client) Sign(pos proto.Position) (Sign, bool) {
+	entity, ok := c.World.BlockEntity(pos)
+	if !ok || entity.Data["front_text"] == nil {
+		return Sign{}, false
+	}
+
+	waxed, _ := entity.Data["is_waxed"].(int8)
+	return Sign{
+		Front: signText(entity.Data["front_text"]),
+		Back:  signText(entity.Data["back_text"]),
+		Waxed: waxed != 0,
+	}, true
+}
+
+// signText decodes a side of a sign, which stores each line as a JSON text
+// component.
+func signText(v any) SignText {
+	compound, _ := v.(map[string]any)
+	glowing, _ := compound["has_glowing_text"].(int8)
+	text := SignText{Color: nbtString(compound["color"]), Glowing: glowing != 0}
+
+	messages, _ := compound["messages"].([]any)
+	for i := 0; i < len(messages) && i < signLines; i++ {
+		text.Lines[i], _ = proto.Chat(nbtString(messages[i])).Component()
+	}
+	return text
+}
+
+// EditSign writes up to four lines of plain text on one side of the sign at
+// pos. The server has to open the sign editor first, which it does right
+// after a sign is placed; otherwise EditSign right-clicks the sign to open
+// it. Waxed signs cannot be edited.
+func (c *Client) EditSign(ctx context.Context, pos proto.Position, front bool, lines ...string) error {
+	if len(lines) > signLines {
+		return fmt.Errorf("sign has %d lines, got %d", signLines, len(lines))
+	}
+	var text [signLines]proto.String
+	for i, line := range lines {
+		if textLength(line) > maxSignLineLength {
+			return fmt.Errorf("sign line %d is longer than %d characters", i+1, maxSignLineLength)
+		}
+		text[i] = proto.String(line)
+	}
+
+	if !c.signEditor.take(pos) {
+		if err := c.openSignEditor(ctx, pos); err != nil {
+			return err
+		}
+		c.signEditor.take(pos)
+	}
+
+	packet := proto.NewPacket(0x2e)
+	err := packet.Append(&RequestUpdateSign{
+		Location:    pos,
+		IsFrontText: proto.Bool(front),
+		Line1:       text[0],
+		Line2:       text[1],
+		Line3:       text[2],
+		Line4:       text[3],
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+// openSignEditor right-clicks the sign at pos and waits for the server to
+// open the editor.
+func (c *Client) openSignEditor(ctx context.Context, pos proto.Position) error {
+	opened := make(chan struct{}, 1)
+	unsubscribe := c.Subscribe(func(event Event) {
+		if e, ok := event.(SignEditorOpenedEvent); ok && e.Position == pos {
+			select {
+			case opened <- struct{}{}:
+			default:
+			}
+		}
+	})
+	defer unsubscribe()
+
+	if err := c.ActivateBlock(ctx, pos); err != nil {
+		return err
+	}
+
+	// the editor is opened before the click is acknowledged
+	select {
+	case <-opened:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return ErrSignNotEditable
+	}
+}
+
+func (c *Client) handleOpenSignEditor(pk proto.Packet) error {
+	var editor proto.OpenSignEditorResponse
+	if err := pk.Scan(&editor); err != nil {
+		return err
+	}
+
+	c.signEditor.set(editor.Location)
+	c.emit(SignEditorOpenedEvent{Position: editor.Location, Front: bool(editor.IsFrontText)})
+	return nil
+}
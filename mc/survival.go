@@ -0,0 +1,217 @@
+package mc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mc-bot/mc/proto"
+	"time"
+)
+
+const (
+	// maxFood is the food level of a full hunger bar.
+	maxFood = 20
+	// defaultEatTicks is how long eating most food takes.
+	defaultEatTicks = 32
+	// defaultFoodThreshold keeps the food level high enough to regenerate
+	// health, which needs 18, with room for one meal.
+	defaultFoodThreshold = 14
+)
+
+// harmfulFood is food with bad effects that is only eaten when there is
+// nothing else.
+var harmfulFood = map[string]bool{
+	"minecraft:rotten_flesh":     true,
+	"minecraft:spider_eye":       true,
+	"minecraft:poisonous_potato": true,
+	"minecraft:pufferfish":       true,
+	"minecraft:chicken":          true,
+	"minecraft:suspicious_stew":  true,
+}
+
+// SurvivalOptions configures RunSurvival.
+type SurvivalOptions struct {
+	// FoodThreshold is the food level at which the player eats, 14 when 0.
+	FoodThreshold int32
+	// HealthThreshold makes the player also eat when health is below it and
+	// the hunger bar is not full, to regenerate faster. 0 disables it.
+	HealthThreshold float32
+	// Interval is how often needs are checked, one second when 0.
+	Interval time.Duration
+}
+
+// RunSurvival keeps the player fed until ctx is done. Eating interrupted by
+// e.g. switching items or opening a container is retried later. Client.Items
+// must be set to tell food apart.
+func (c *Client) RunSurvival(ctx context.Context, opts SurvivalOptions) error {
+	if c.Items == nil {
+		return ErrNoItemRegistry
+	}
+
+	threshold := opts.FoodThreshold
+	if threshold == 0 {
+		threshold = defaultFoodThreshold
+	}
+	interval := opts.Interval
+	if interval == 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	warned := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		health, food, _ := c.Player.Health()
+		hungry := food <= threshold || (health < opts.HealthThreshold && food < maxFood)
+		if c.Dead() || !hungry {
+			warned = false
+			continue
+		}
+
+		err := c.Eat(ctx)
+		switch {
+		case errors.Is(err, ErrNoFood):
+			if !warned {
+				log.Printf("[WARN] Player '%s' is hungry and has no food", c.Player.Name())
+				warned = true
+			}
+		case errors.Is(err, ErrEatInterrupted), errors.Is(err, ErrContainerOpen):
+		case err != nil:
+			return err
+		}
+	}
+}
+
+// Eat eats the best food in the player inventory, moving it to the held
+// hotbar slot if needed, and waits until it is eaten. Client.Items must be
+// set.
+func (c *Client) Eat(ctx context.Context) error {
+	if c.Items == nil {
+		return ErrNoItemRegistry
+	}
+	if _, open := c.Inventory.Open(); open {
+		return ErrContainerOpen
+	}
+
+	_, food, _ := c.Player.Health()
+	slot, ok := c.bestFood(food)
+	if !ok {
+		return ErrNoFood
+	}
+
+	hand := HandMain
+	held := int(c.Player.HeldSlot())
+	switch {
+	case slot == SlotOffhand:
+		hand = HandOff
+	case slot >= SlotHotbar && slot < SlotHotbar+9:
+		if err := c.SelectHotbarSlot(slot - SlotHotbar); err != nil {
+			return err
+		}
+		held = slot - SlotHotbar
+	default:
+		if err := c.SwapHotbar(slot, held); err != nil {
+			return err
+		}
+		slot = SlotHotbar + held
+	}
+
+	item := c.Inventory.Slot(slot)
+	ticks := c.items().Item(int32(item.ItemID)).EatTicks
+	if ticks <= 0 {
+		ticks = defaultEatTicks
+	}
+
+	if err := c.UseItem(hand); err != nil {
+		return err
+	}
+	err := c.waitEaten(ctx, slot, item, hand == HandMain, held, time.Duration(ticks)*TickDuration+ackTimeout)
+	if err != nil {
+		if releaseErr := c.ReleaseUseItem(); releaseErr != nil {
+			return releaseErr
+		}
+	}
+	return err
+}
+
+// waitEaten waits until the server takes the eaten item out of slot.
+func (c *Client) waitEaten(ctx context.Context, slot int, item proto.Slot, checkHeld bool, held int, timeout time.Duration) error {
+	ticker := time.NewTicker(TickDuration)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			return ErrEatInterrupted
+		case <-ticker.C:
+		}
+
+		current := c.Inventory.Slot(slot)
+		switch {
+		case sameItem(current, item) && !current.Empty() && current.Count < item.Count:
+			return nil
+		case !sameItem(current, item) || current.Empty():
+			// the last item of a stack is eaten or replaced by a bowl
+			if item.Count == 1 {
+				return nil
+			}
+			return ErrEatInterrupted
+		}
+
+		if c.Dead() || (checkHeld && int(c.Player.HeldSlot()) != held) {
+			return ErrEatInterrupted
+		}
+		if _, open := c.Inventory.Open(); open {
+			return ErrEatInterrupted
+		}
+	}
+}
+
+// bestFood returns the player inventory slot with the food that restores
+// the most saturation without wasting hunger points, preferring food
+// without bad effects.
+func (c *Client) bestFood(food int32) (int, bool) {
+	if food >= maxFood {
+		return 0, false
+	}
+
+	best, bestScore := -1, 0.0
+	for i := SlotMain; i <= SlotOffhand; i++ {
+		slot := c.Inventory.Slot(i)
+		if slot.Empty() {
+			continue
+		}
+		item := c.items().Item(int32(slot.ItemID))
+		if item.Food <= 0 {
+			continue
+		}
+
+		score := float64(item.Saturation)
+		if missing := int(maxFood - food); item.Food > missing {
+			// wasted hunger points count against the food
+			score -= float64(item.Food - missing)
+		}
+		if harmfulFood[item.Name] {
+			score -= 100
+		}
+		// held food does not need to be moved
+		if i == SlotHotbar+int(c.Player.HeldSlot()) || i == SlotOffhand {
+			score += 0.01
+		}
+
+		if best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, best >= 0
+}
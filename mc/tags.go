@@ -0,0 +1,111 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registries with tags sent in Update Tags.
+const (
+	RegistryBlock      = "minecraft:block"
+	RegistryItem       = "minecraft:item"
+	RegistryFluid      = "minecraft:fluid"
+	RegistryEntityType = "minecraft:entity_type"
+	RegistryGameEvent  = "minecraft:game_event"
+)
+
+// Tags resolves tags like "minecraft:logs" to registry IDs. It is safe for
+// concurrent use.
+type Tags struct {
+	mu   sync.RWMutex
+	tags map[string]map[string]map[int32]bool // registry -> tag -> IDs
+}
+
+func newTags() *Tags {
+	return &Tags{tags: map[string]map[string]map[int32]bool{}}
+}
+
+// Get returns the IDs in tag of registry, sorted. The tag may be written
+// with or without the leading '#'.
+func (t *Tags) Get(registry, tag string) []int32 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	entries := t.tags[registry][tagName(tag)]
+	out := make([]int32, 0, len(entries))
+	for id := range entries {
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Has reports whether ID id of registry is in tag.
+func (t *Tags) Has(registry, tag string, id int32) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tags[registry][tagName(tag)][id]
+}
+
+// Names returns the names of all tags of registry, sorted.
+func (t *Tags) Names(registry string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]string, 0, len(t.tags[registry]))
+	for name := range t.tags[registry] {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func tagName(tag string) string {
+	return strings.TrimPrefix(tag, "#")
+}
+
+// BlockHasTag reports whether the block at pos is in a block tag. Client.Blocks
+// must be set and provide Block.ID.
+func (c *Client) BlockHasTag(pos proto.Position, tag string) (bool, error) {
+	if c.Blocks == nil {
+		return false, ErrNoBlockRegistry
+	}
+	return c.Tags.Has(RegistryBlock, tag, c.block(pos).ID), nil
+}
+
+// ItemHasTag reports whether the item in slot is in an item tag.
+func (c *Client) ItemHasTag(slot proto.Slot, tag string) bool {
+	return !slot.Empty() && c.Tags.Has(RegistryItem, tag, int32(slot.ItemID))
+}
+
+// EntityHasTag reports whether the type of entity is in an entity type tag.
+func (c *Client) EntityHasTag(entity Entity, tag string) bool {
+	return c.Tags.Has(RegistryEntityType, tag, entity.Type)
+}
+
+func (c *Client) handleUpdateTags(pk proto.Packet) error {
+	var update proto.UpdateTagsResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	t := c.Tags
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// the server always sends all tags of a registry
+	for _, registry := range update.Registries {
+		tags := make(map[string]map[int32]bool, len(registry.Tags))
+		for _, tag := range registry.Tags {
+			entries := make(map[int32]bool, len(tag.Entries))
+			for _, id := range tag.Entries {
+				entries[int32(id)] = true
+			}
+			tags[string(tag.Name)] = entries
+		}
+		t.tags[string(registry.Registry)] = tags
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package mc
+
+import (
+	"context"
+	"math"
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+// Slots of the merchant window.
+const (
+	SlotTradeInput1 = 0
+	SlotTradeInput2 = 1
+	SlotTradeResult = 2
+)
+
+// Merchant holds the trades of the villager or wandering trader the player
+// trades with.
+type Merchant struct {
+	WindowID   int32
+	Offers     []proto.MerchantOffer
+	Level      int32 // Level is the villager level, 1 (novice) to 5 (master), 0 for wandering traders
+	Experience int32
+	IsVillager bool
+	CanRestock bool
+}
+
+// MerchantOffersEvent is emitted when the server sends the trades of the
+// open merchant window, when it is opened and after trades.
+type MerchantOffersEvent struct {
+	Merchant Merchant
+}
+
+type merchant struct {
+	mu       sync.Mutex
+	merchant *Merchant
+}
+
+// Merchant returns the trades of the open merchant window.
+func (c *Client) Merchant() (Merchant, bool) {
+	window, open := c.Inventory.Open()
+	c.merchant.mu.Lock()
+	defer c.merchant.mu.Unlock()
+
+	m := c.merchant.merchant
+	if !open || m == nil || m.WindowID != window.ID {
+		return Merchant{}, false
+	}
+	out := *m
+	out.Offers = append([]proto.MerchantOffer(nil), m.Offers...)
+	return out, true
+}
+
+// TradeCost returns the first input of an offer with its count adjusted by
+// demand and special price, which is what the trade actually costs. It
+// follows the vanilla MerchantOffer#getCostA.
+func (c *Client) TradeCost(offer proto.MerchantOffer) proto.Slot {
+	count := int(offer.Input1.Count)
+	demand := int(math.Max(0, math.Floor(float64(count)*float64(offer.Demand)*float64(offer.PriceMultiplier))))
+	count += demand + int(offer.SpecialPrice)
+
+	if max := maxStack(c.items(), offer.Input1); count > max {
+		count = max
+	}
+	return withCount(offer.Input1, int(math.Max(1, float64(count))))
+}
+
+// Trade executes offer of the open merchant window times times. The
+// server takes the inputs from the player inventory; the results are put
+// into it.
+func (c *Client) Trade(ctx context.Context, offer, times int) error {
+	merchant, ok := c.Merchant()
+	if !ok {
+		return ErrNoMerchant
+	}
+	if offer < 0 || offer >= len(merchant.Offers) {
+		return ErrUnknownTrade
+	}
+
+	for i := 0; i < times; i++ {
+		if merchant, ok = c.Merchant(); !ok {
+			return ErrNoMerchant
+		}
+		if o := merchant.Offers[offer]; o.Disabled || o.Uses >= o.MaxUses {
+			return ErrTradeUnavailable
+		}
+
+		// selecting a trade moves its inputs from the inventory into the
+		// input slots
+		if err := c.selectTrade(offer); err != nil {
+			return err
+		}
+		err := c.waitSlot(ctx, SlotTradeResult, func(slot proto.Slot) bool { return !slot.Empty() })
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the ack timeout ran out, the server has no result for the inputs
+			return ErrMissingIngredients
+		}
+
+		if err := c.click(SlotTradeResult, 0, ClickPickup); err != nil {
+			return err
+		}
+		c.merchant.used(merchant.WindowID, offer)
+		if err := c.stowCarried(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// used counts a trade, which the server does not send offers for.
+func (m *merchant) used(windowID int32, offer int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.merchant != nil && m.merchant.WindowID == windowID {
+		m.merchant.Offers[offer].Uses++
+	}
+}
+
+func (c *Client) selectTrade(offer int) error {
+	packet := proto.NewPacket(0x26)
+	if err := packet.Append(&RequestSelectTrade{Slot: proto.VarInt(offer)}); err != nil {
+		return err
+	}
+
+	return c.SendPacket(packet)
+}
+
+func (c *Client) handleMerchantOffers(pk proto.Packet) error {
+	var offers proto.MerchantOffersResponse
+	if err := pk.Scan(&offers); err != nil {
+		return err
+	}
+
+	m := Merchant{
+		WindowID:   int32(offers.WindowID),
+		Offers:     offers.Offers,
+		Level:      int32(offers.Level),
+		Experience: int32(offers.Experience),
+		IsVillager: bool(offers.IsVillager),
+		CanRestock: bool(offers.CanRestock),
+	}
+	c.merchant.mu.Lock()
+	c.merchant.merchant = &m
+	c.merchant.mu.Unlock()
+
+	m.Offers = append([]proto.MerchantOffer(nil), m.Offers...)
+	c.emit(MerchantOffersEvent{Merchant: m})
+	return nil
+}
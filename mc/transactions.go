@@ -0,0 +1,380 @@
+package mc
+
+import (
+	"fmt"
+	"mc-bot/mc/proto"
+	"reflect"
+)
+
+// HotbarOffhand is the SwapHotbar button that swaps with the offhand.
+const HotbarOffhand = 40
+
+// Item helpers below work on the current window, see Inventory.Current.
+// They predict the outcome of each click like the vanilla client does and
+// rely on the server sending the window back when the prediction was wrong.
+
+// MoveItems moves count items from one slot to another. A count of 0 or
+// more than the stack moves the whole stack, which is swapped with the
+// target when it holds a different item.
+func (c *Client) MoveItems(from, to, count int) error {
+	if !c.Inventory.Carried().Empty() {
+		return ErrCursorNotEmpty
+	}
+
+	window := c.Inventory.Current()
+	if from < 0 || from >= len(window.Slots) || to < 0 || to >= len(window.Slots) {
+		return fmt.Errorf("slot out of window with %d slots", len(window.Slots))
+	}
+
+	src, dst := window.Slots[from], window.Slots[to]
+	if src.Empty() {
+		return ErrSlotEmpty
+	}
+	whole := count <= 0 || count >= int(src.Count)
+	if !whole && !dst.Empty() && !sameItem(src, dst) {
+		return ErrSlotOccupied
+	}
+
+	if err := c.click(from, 0, ClickPickup); err != nil {
+		return err
+	}
+	if whole {
+		if err := c.click(to, 0, ClickPickup); err != nil {
+			return err
+		}
+	} else {
+		for i := 0; i < count; i++ {
+			if err := c.click(to, 1, ClickPickup); err != nil {
+				return err
+			}
+		}
+	}
+
+	// put back the rest or the swapped item
+	if !c.Inventory.Carried().Empty() {
+		return c.click(from, 0, ClickPickup)
+	}
+	return nil
+}
+
+// Move moves count items from one slot of the current window to another like
+// Client.MoveItems. It needs the client to be connected.
+func (inv *Inventory) Move(from, to, count int) error {
+	inv.mu.Lock()
+	client := inv.client
+	inv.mu.Unlock()
+
+	if client == nil {
+		return ErrNotConnected
+	}
+	return client.MoveItems(from, to, count)
+}
+
+// ShiftClick quickly moves the stack in slot between the container and the
+// player inventory, or between the hotbar and the main inventory.
+func (c *Client) ShiftClick(slot int) error {
+	return c.click(slot, 0, ClickQuickMove)
+}
+
+// SwapHotbar swaps slot with hotbar slot 0-8 or HotbarOffhand.
+func (c *Client) SwapHotbar(slot, hotbar int) error {
+	if (hotbar < 0 || hotbar > 8) && hotbar != HotbarOffhand {
+		return fmt.Errorf("invalid hotbar slot %d", hotbar)
+	}
+	return c.click(slot, int8(hotbar), ClickSwap)
+}
+
+// DropItem throws one item out of slot.
+func (c *Client) DropItem(slot int) error {
+	return c.click(slot, 0, ClickThrow)
+}
+
+// DropStack throws the whole stack out of slot.
+func (c *Client) DropStack(slot int) error {
+	return c.click(slot, 1, ClickThrow)
+}
+
+// Equip moves the armor piece in slot of the player inventory to its armor
+// slot, swapping it with the worn one. Containers have no armor slots, so
+// the player inventory has to be the current window.
+func (c *Client) Equip(slot int) error {
+	if _, open := c.Inventory.Open(); open {
+		return ErrContainerOpen
+	}
+
+	item := c.Inventory.Slot(slot)
+	if item.Empty() {
+		return ErrSlotEmpty
+	}
+	armor := c.items().Item(int32(item.ItemID)).ArmorSlot
+	if armor == 0 {
+		return ErrNotEquipment
+	}
+	if armor == slot {
+		return nil
+	}
+	return c.MoveItems(slot, armor, 0)
+}
+
+// SelectHotbarSlot changes the held item to hotbar slot 0-8.
+func (c *Client) SelectHotbarSlot(slot int) error {
+	if slot < 0 || slot > 8 {
+		return fmt.Errorf("invalid hotbar slot %d", slot)
+	}
+
+	packet := proto.NewPacket(0x28)
+	if err := packet.Append(&RequestSetHeldItem{Slot: proto.Short(slot)}); err != nil {
+		return err
+	}
+
+	if err := c.SendPacket(packet); err != nil {
+		return err
+	}
+	c.Player.update(func(state *PlayerState) {
+		state.HeldSlot = int8(slot)
+	})
+	c.combat.reset()
+	return nil
+}
+
+// click predicts the outcome of a click on slot of the current window and
+// sends it with ClickContainer.
+func (c *Client) click(slot int, button int8, mode ClickMode) error {
+	inv := c.Inventory
+	inv.mu.Lock()
+	w := inv.current()
+	before := append([]proto.Slot(nil), w.Slots...)
+	sim := clickSimulation{
+		slots:    append([]proto.Slot(nil), before...),
+		carried:  inv.carried,
+		offhand:  inv.player.Slots[SlotOffhand],
+		items:    c.items(),
+		player:   w.ID == PlayerWindowID,
+		crafting: w.ID == PlayerWindowID || w.Type == MenuCrafting,
+		offset:   w.playerOffset(),
+	}
+	id, offhand := w.ID, sim.offhand
+	inv.mu.Unlock()
+
+	if slot != SlotOutside && (slot < 0 || slot >= len(sim.slots)) {
+		return fmt.Errorf("slot %d out of window with %d slots", slot, len(sim.slots))
+	}
+	sim.click(slot, button, mode)
+
+	changed := map[int16]proto.Slot{}
+	for i := range sim.slots {
+		if !reflect.DeepEqual(before[i], sim.slots[i]) {
+			changed[int16(i)] = sim.slots[i]
+		}
+	}
+	if err := c.ClickContainer(id, int16(slot), button, mode, changed, sim.carried); err != nil {
+		return err
+	}
+
+	// container windows leave the offhand out, so its change is not part of
+	// the click and only applied locally
+	if !sim.player && !reflect.DeepEqual(offhand, sim.offhand) {
+		inv.mu.Lock()
+		inv.setSlot(inv.player, SlotOffhand, sim.offhand)
+		inv.mu.Unlock()
+	}
+	return nil
+}
+
+// clickSimulation applies clicks to a copy of a window following the vanilla
+// container logic.
+type clickSimulation struct {
+	slots    []proto.Slot
+	carried  proto.Slot
+	offhand  proto.Slot // offhand is the player offhand, which only the player window includes
+	items    ItemRegistry
+	player   bool // player is set for the player inventory window
+	crafting bool // crafting is set for windows with the crafting output in slot 0
+	offset   int  // offset is the index of the first main inventory slot
+}
+
+func (s *clickSimulation) click(slot int, button int8, mode ClickMode) {
+	switch mode {
+	case ClickPickup:
+		if slot == SlotOutside {
+			s.dropCarried(button == 0)
+		} else {
+			s.pickup(slot, button == 1)
+		}
+	case ClickQuickMove:
+		s.quickMove(slot)
+	case ClickSwap:
+		s.swap(slot, int(button))
+	case ClickThrow:
+		if s.carried.Empty() && slot != SlotOutside {
+			if button == 1 {
+				s.slots[slot] = proto.Slot{}
+			} else {
+				s.slots[slot] = withCount(s.slots[slot], int(s.slots[slot].Count)-1)
+			}
+		}
+	}
+}
+
+func (s *clickSimulation) dropCarried(all bool) {
+	if all {
+		s.carried = proto.Slot{}
+	} else {
+		s.carried = withCount(s.carried, int(s.carried.Count)-1)
+	}
+}
+
+func (s *clickSimulation) pickup(i int, right bool) {
+	slot := s.slots[i]
+	result := s.crafting && i == SlotCraftingResult
+
+	switch {
+	case s.carried.Empty():
+		if slot.Empty() {
+			return
+		}
+		take := int(slot.Count)
+		if right && !result {
+			take = (take + 1) / 2
+		}
+		s.carried = withCount(slot, take)
+		s.slots[i] = withCount(slot, int(slot.Count)-take)
+	case result:
+		// crafting output can only be taken
+		if sameItem(slot, s.carried) && int(s.carried.Count+slot.Count) <= s.maxStack(slot) {
+			s.carried = withCount(s.carried, int(s.carried.Count+slot.Count))
+			s.slots[i] = proto.Slot{}
+		}
+	case slot.Empty() || sameItem(slot, s.carried):
+		put := int(s.carried.Count)
+		if right {
+			put = 1
+		}
+		if room := s.maxStack(s.carried) - int(slot.Count); put > room {
+			put = room
+		}
+		if put <= 0 {
+			return
+		}
+		s.slots[i] = withCount(s.carried, int(slot.Count)+put)
+		s.carried = withCount(s.carried, int(s.carried.Count)-put)
+	default:
+		s.slots[i], s.carried = s.carried, slot
+	}
+}
+
+func (s *clickSimulation) swap(i, button int) {
+	j := s.offset + 27 + button
+	if button == HotbarOffhand {
+		if !s.player {
+			s.slots[i], s.offhand = s.offhand, s.slots[i]
+			return
+		}
+		j = SlotOffhand
+	}
+	if j < 0 || j >= len(s.slots) || i == j {
+		return
+	}
+	s.slots[i], s.slots[j] = s.slots[j], s.slots[i]
+}
+
+func (s *clickSimulation) quickMove(i int) {
+	slot := s.slots[i]
+	if slot.Empty() {
+		return
+	}
+
+	end := len(s.slots)
+	if s.player {
+		end = SlotOffhand
+	}
+
+	var rest proto.Slot
+	switch {
+	case !s.player && i < s.offset:
+		rest = s.moveTo(slot, s.offset, end, true)
+	case !s.player:
+		rest = s.moveTo(slot, 0, s.offset, false)
+	case i == SlotCraftingResult:
+		rest = s.moveTo(slot, SlotMain, end, true)
+	case i < SlotMain || i == SlotOffhand:
+		rest = s.moveTo(slot, SlotMain, end, false)
+	default:
+		rest = slot
+		if armor := s.items.Item(int32(slot.ItemID)).ArmorSlot; armor != 0 && s.slots[armor].Empty() {
+			rest = s.moveTo(rest, armor, armor+1, false)
+		}
+		if i < SlotHotbar {
+			rest = s.moveTo(rest, SlotHotbar, end, false)
+		} else {
+			rest = s.moveTo(rest, SlotMain, SlotHotbar, false)
+		}
+	}
+	s.slots[i] = rest
+}
+
+// moveTo puts stack into slots from start to end, first merging with equal
+// items and then into the first empty slot. It returns what did not fit.
+func (s *clickSimulation) moveTo(stack proto.Slot, start, end int, reverse bool) proto.Slot {
+	order := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		order = append(order, i)
+	}
+	if reverse {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	max := s.maxStack(stack)
+	for _, i := range order {
+		if stack.Empty() {
+			return stack
+		}
+		if slot := s.slots[i]; !slot.Empty() && sameItem(slot, stack) && int(slot.Count) < max {
+			put := max - int(slot.Count)
+			if put > int(stack.Count) {
+				put = int(stack.Count)
+			}
+			s.slots[i] = withCount(slot, int(slot.Count)+put)
+			stack = withCount(stack, int(stack.Count)-put)
+		}
+	}
+	for _, i := range order {
+		if stack.Empty() {
+			return stack
+		}
+		if s.slots[i].Empty() {
+			s.slots[i] = stack
+			return proto.Slot{}
+		}
+	}
+	return stack
+}
+
+func (s *clickSimulation) maxStack(slot proto.Slot) int {
+	return maxStack(s.items, slot)
+}
+
+// maxStack returns how many items fit in one slot with slot's item.
+func maxStack(items ItemRegistry, slot proto.Slot) int {
+	if max := items.Item(int32(slot.ItemID)).MaxStack; max > 0 {
+		return max
+	}
+	return 64
+}
+
+// sameItem reports whether two stacks can be merged.
+func sameItem(a, b proto.Slot) bool {
+	return a.ItemID == b.ItemID && reflect.DeepEqual(a.NBT, b.NBT)
+}
+
+// withCount returns slot with a different item count, empty for 0 or less.
+func withCount(slot proto.Slot, count int) proto.Slot {
+	if count <= 0 {
+		return proto.Slot{}
+	}
+	slot.Present = true
+	slot.Count = proto.Byte(count)
+	return slot
+}
@@ -0,0 +1,238 @@
+package mc
+
+import (
+	"mc-bot/mc/proto"
+	"reflect"
+	"testing"
+)
+
+const (
+	testStone  = 1
+	testDirt   = 2
+	testPearl  = 3 // testPearl stacks to 16
+	testHelmet = 4
+)
+
+type testItems map[int32]Item
+
+func (items testItems) Item(id int32) Item {
+	if item, ok := items[id]; ok {
+		return item
+	}
+	return Item{MaxStack: 64}
+}
+
+func stack(id, count int) proto.Slot {
+	return proto.Slot{Present: true, ItemID: proto.VarInt(id), Count: proto.Byte(count)}
+}
+
+func TestClickSimulation(t *testing.T) {
+	items := testItems{
+		testPearl:  {MaxStack: 16},
+		testHelmet: {MaxStack: 1, ArmorSlot: SlotHelmet},
+	}
+	// chest is a 27 slot container followed by the 36 player slots
+	const chestSize, chestHotbar = 27 + 36, 27 + 27
+
+	tests := []struct {
+		Name    string
+		Chest   bool
+		Slots   map[int]proto.Slot // Slots are the non-empty slots before the click
+		Carried proto.Slot
+		Offhand proto.Slot // Offhand is the player offhand in container windows
+		Slot    int
+		Button  int8
+		Mode    ClickMode
+
+		Want        map[int]proto.Slot // Want are the non-empty slots after the click
+		WantCarried proto.Slot
+		WantOffhand proto.Slot
+	}{
+		{
+			Name:  "pickup stack",
+			Slots: map[int]proto.Slot{SlotMain: stack(testStone, 10)},
+			Slot:  SlotMain, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{},
+			WantCarried: stack(testStone, 10),
+		},
+		{
+			Name:  "pickup half rounds up",
+			Slots: map[int]proto.Slot{SlotMain: stack(testStone, 5)},
+			Slot:  SlotMain, Button: 1, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{SlotMain: stack(testStone, 2)},
+			WantCarried: stack(testStone, 3),
+		},
+		{
+			Name:    "place one",
+			Slots:   map[int]proto.Slot{SlotMain: stack(testStone, 5)},
+			Carried: stack(testStone, 3),
+			Slot:    SlotMain, Button: 1, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{SlotMain: stack(testStone, 6)},
+			WantCarried: stack(testStone, 2),
+		},
+		{
+			Name:    "place up to stack limit",
+			Slots:   map[int]proto.Slot{SlotMain: stack(testPearl, 10)},
+			Carried: stack(testPearl, 10),
+			Slot:    SlotMain, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{SlotMain: stack(testPearl, 16)},
+			WantCarried: stack(testPearl, 4),
+		},
+		{
+			Name:    "swap with cursor",
+			Slots:   map[int]proto.Slot{SlotMain: stack(testStone, 5)},
+			Carried: stack(testDirt, 3),
+			Slot:    SlotMain, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{SlotMain: stack(testDirt, 3)},
+			WantCarried: stack(testStone, 5),
+		},
+		{
+			Name:    "take crafting result",
+			Slots:   map[int]proto.Slot{SlotCraftingResult: stack(testStone, 4)},
+			Carried: stack(testStone, 60),
+			Slot:    SlotCraftingResult, Button: 1, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{},
+			WantCarried: stack(testStone, 64),
+		},
+		{
+			Name:    "crafting result does not fit",
+			Slots:   map[int]proto.Slot{SlotCraftingResult: stack(testStone, 4)},
+			Carried: stack(testStone, 61),
+			Slot:    SlotCraftingResult, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{SlotCraftingResult: stack(testStone, 4)},
+			WantCarried: stack(testStone, 61),
+		},
+		{
+			Name:    "drop one outside",
+			Carried: stack(testStone, 3),
+			Slot:    SlotOutside, Button: 1, Mode: ClickPickup,
+			Want:        map[int]proto.Slot{},
+			WantCarried: stack(testStone, 2),
+		},
+		{
+			Name:    "drop all outside",
+			Carried: stack(testStone, 3),
+			Slot:    SlotOutside, Mode: ClickPickup,
+			Want: map[int]proto.Slot{},
+		},
+		{
+			Name: "quick move main to hotbar merges first",
+			Slots: map[int]proto.Slot{
+				SlotMain:       stack(testPearl, 10),
+				SlotHotbar + 3: stack(testPearl, 14),
+				SlotHotbar + 5: stack(testPearl, 15),
+			},
+			Slot: SlotMain, Mode: ClickQuickMove,
+			Want: map[int]proto.Slot{
+				SlotHotbar:     stack(testPearl, 7),
+				SlotHotbar + 3: stack(testPearl, 16),
+				SlotHotbar + 5: stack(testPearl, 16),
+			},
+		},
+		{
+			Name:  "quick move hotbar to main",
+			Slots: map[int]proto.Slot{SlotHotbar: stack(testStone, 10)},
+			Slot:  SlotHotbar, Mode: ClickQuickMove,
+			Want: map[int]proto.Slot{SlotMain: stack(testStone, 10)},
+		},
+		{
+			Name:  "quick move armor",
+			Slots: map[int]proto.Slot{SlotMain + 4: stack(testHelmet, 1)},
+			Slot:  SlotMain + 4, Mode: ClickQuickMove,
+			Want: map[int]proto.Slot{SlotHelmet: stack(testHelmet, 1)},
+		},
+		{
+			Name:  "quick move chest to player fills from the hotbar end",
+			Chest: true,
+			Slots: map[int]proto.Slot{0: stack(testStone, 10)},
+			Slot:  0, Mode: ClickQuickMove,
+			Want: map[int]proto.Slot{chestSize - 1: stack(testStone, 10)},
+		},
+		{
+			Name:  "quick move player to chest",
+			Chest: true,
+			Slots: map[int]proto.Slot{chestHotbar: stack(testStone, 10), 4: stack(testStone, 60)},
+			Slot:  chestHotbar, Mode: ClickQuickMove,
+			Want: map[int]proto.Slot{0: stack(testStone, 6), 4: stack(testStone, 64)},
+		},
+		{
+			Name:  "swap with hotbar",
+			Slots: map[int]proto.Slot{SlotMain: stack(testStone, 10), SlotHotbar + 2: stack(testDirt, 1)},
+			Slot:  SlotMain, Button: 2, Mode: ClickSwap,
+			Want: map[int]proto.Slot{SlotMain: stack(testDirt, 1), SlotHotbar + 2: stack(testStone, 10)},
+		},
+		{
+			Name:  "swap with offhand",
+			Slots: map[int]proto.Slot{SlotMain: stack(testStone, 10)},
+			Slot:  SlotMain, Button: HotbarOffhand, Mode: ClickSwap,
+			Want: map[int]proto.Slot{SlotOffhand: stack(testStone, 10)},
+		},
+		{
+			Name:  "swap with hotbar in chest",
+			Chest: true,
+			Slots: map[int]proto.Slot{3: stack(testStone, 10)},
+			Slot:  3, Button: 8, Mode: ClickSwap,
+			Want: map[int]proto.Slot{chestSize - 1: stack(testStone, 10)},
+		},
+		{
+			Name:    "swap with offhand in chest",
+			Chest:   true,
+			Slots:   map[int]proto.Slot{3: stack(testStone, 10)},
+			Offhand: stack(testDirt, 2),
+			Slot:    3, Button: HotbarOffhand, Mode: ClickSwap,
+			Want:        map[int]proto.Slot{3: stack(testDirt, 2)},
+			WantOffhand: stack(testStone, 10),
+		},
+		{
+			Name:  "throw one",
+			Slots: map[int]proto.Slot{SlotMain: stack(testStone, 10)},
+			Slot:  SlotMain, Mode: ClickThrow,
+			Want: map[int]proto.Slot{SlotMain: stack(testStone, 9)},
+		},
+		{
+			Name:  "throw stack",
+			Slots: map[int]proto.Slot{SlotMain: stack(testStone, 10)},
+			Slot:  SlotMain, Button: 1, Mode: ClickThrow,
+			Want: map[int]proto.Slot{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			sim := clickSimulation{
+				slots:    make([]proto.Slot, playerInventorySize),
+				carried:  tt.Carried,
+				offhand:  tt.Offhand,
+				items:    items,
+				player:   true,
+				crafting: true,
+				offset:   SlotMain,
+			}
+			if tt.Chest {
+				sim.slots = make([]proto.Slot, chestSize)
+				sim.player, sim.crafting, sim.offset = false, false, 27
+			}
+			for i, slot := range tt.Slots {
+				sim.slots[i] = slot
+			}
+
+			sim.click(tt.Slot, tt.Button, tt.Mode)
+
+			got := map[int]proto.Slot{}
+			for i, slot := range sim.slots {
+				if !slot.Empty() {
+					got[i] = slot
+				}
+			}
+			if !reflect.DeepEqual(tt.Want, got) {
+				t.Errorf("Want slots: %v, Got: %v", tt.Want, got)
+			}
+			if !reflect.DeepEqual(tt.WantCarried, sim.carried) {
+				t.Errorf("Want carried: %v, Got: %v", tt.WantCarried, sim.carried)
+			}
+			if !reflect.DeepEqual(tt.WantOffhand, sim.offhand) {
+				t.Errorf("Want offhand: %v, Got: %v", tt.WantOffhand, sim.offhand)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package mc
+
+import (
+	"bytes"
+	"fmt"
+	"mc-bot/mc/proto"
+	"sync"
+)
+
+// AABB is an axis aligned bounding box.
+type AABB struct {
+	MinX, MinY, MinZ float64
+	MaxX, MaxY, MaxZ float64
+}
+
+func (a AABB) Offset(x, y, z float64) AABB {
+	return AABB{a.MinX + x, a.MinY + y, a.MinZ + z, a.MaxX + x, a.MaxY + y, a.MaxZ + z}
+}
+
+func (a AABB) Intersects(b AABB) bool {
+	return a.MinX < b.MaxX && a.MaxX > b.MinX &&
+		a.MinY < b.MaxY && a.MaxY > b.MinY &&
+		a.MinZ < b.MaxZ && a.MaxZ > b.MinZ
+}
+
+// FullCube is the shape of a solid block.
+var FullCube = []AABB{{0, 0, 0, 1, 1, 1}}
+
+type Fluid byte
+
+const (
+	FluidNone Fluid = iota
+	FluidWater
+	FluidLava
+)
+
+// Block describes a block state.
+type Block struct {
+	ID     int32  // ID is the index in the minecraft:block registry, -1 when not known
+	Name   string // Name is e.g. "minecraft:stone", empty when not known
+	Shapes []AABB // Shapes is the collision shape in block coordinates
+	Fluid  Fluid
+
+	Climbable bool // Climbable is set for ladders, vines and similar blocks
+	Openable  bool // Openable is set for doors and gates players open by hand
+	Open      bool // Open is set for openable blocks in the open=true state
+
+	Hardness     float32  // Hardness is -1 for unbreakable blocks
+	Tool         ToolType // Tool is the tool type that breaks the block faster
+	RequiresTool bool     // RequiresTool is set when the block drops nothing without Tool
+	HarvestTier  int      // HarvestTier is the lowest tool tier the block drops with
+}
+
+func (b Block) Solid() bool {
+	return len(b.Shapes) > 0
+}
+
+// BlockRegistry describes block states. The protocol only carries state IDs,
+// so the properties of blocks have to come from data generated for the
+// server version, see https://wiki.vg/Data_Generators.
+type BlockRegistry interface {
+	Block(state int32) Block
+}
+
+// BlockStateAir is the state of air, which is also what unloaded blocks read
+// as.
+const BlockStateAir = 0
+
+type ChunkPos struct {
+	X, Z int32
+}
+
+type Chunk struct {
+	Sections      []proto.ChunkSection
+	BlockEntities map[proto.Position]BlockEntity
+}
+
+// BlockEntity holds the extra data of blocks like signs, chests and banners
+// that the server shares with clients.
+type BlockEntity struct {
+	Type int32 // Type is an ID from the minecraft:block_entity_type registry
+	Data map[string]any
+}
+
+// World holds the chunks sent by the server. It is safe for concurrent use.
+type World struct {
+	mu     sync.RWMutex
+	chunks map[ChunkPos]*Chunk
+	minY   int32
+	height int32
+}
+
+func newWorld() *World {
+	// overworld dimensions until the server tells otherwise
+	return &World{chunks: make(map[ChunkPos]*Chunk), minY: -64, height: 384}
+}
+
+// Bounds returns the lowest block Y and the height of the world.
+func (w *World) Bounds() (minY, height int32) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.minY, w.height
+}
+
+func (w *World) sections() int {
+	return int(w.height) / 16
+}
+
+// Loaded reports whether the chunk containing block x, z is loaded.
+func (w *World) Loaded(x, z int32) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	_, ok := w.chunks[ChunkPos{x >> 4, z >> 4}]
+	return ok
+}
+
+// BlockState returns the block state at given position. Positions in
+// unloaded chunks or outside of the world are reported as air.
+func (w *World) BlockState(pos proto.Position) int32 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	section := w.section(pos)
+	if section == nil {
+		return BlockStateAir
+	}
+	return section.BlockStates.Get(blockIndex(pos))
+}
+
+// section returns the section containing pos. Caller must hold the lock.
+func (w *World) section(pos proto.Position) *proto.ChunkSection {
+	chunk, ok := w.chunks[ChunkPos{pos.X >> 4, pos.Z >> 4}]
+	if !ok {
+		return nil
+	}
+
+	i := int((pos.Y - w.minY) >> 4)
+	if pos.Y < w.minY || i >= len(chunk.Sections) {
+		return nil
+	}
+	return &chunk.Sections[i]
+}
+
+func blockIndex(pos proto.Position) int {
+	return int(pos.Y&15)<<8 | int(pos.Z&15)<<4 | int(pos.X&15)
+}
+
+// setBlockState changes the block and returns the previous state. ok is
+// false if the position is not loaded.
+func (w *World) setBlockState(pos proto.Position, state int32) (old int32, ok bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	section := w.section(pos)
+	if section == nil {
+		return BlockStateAir, false
+	}
+
+	i := blockIndex(pos)
+	old = section.BlockStates.Get(i)
+	section.BlockStates.Set(i, state)
+	if state == BlockStateAir {
+		delete(w.chunks[ChunkPos{pos.X >> 4, pos.Z >> 4}].BlockEntities, pos)
+	}
+	return old, true
+}
+
+// BlockEntity returns the block entity at pos.
+func (w *World) BlockEntity(pos proto.Position) (BlockEntity, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	chunk, ok := w.chunks[ChunkPos{pos.X >> 4, pos.Z >> 4}]
+	if !ok {
+		return BlockEntity{}, false
+	}
+	entity, ok := chunk.BlockEntities[pos]
+	return entity, ok
+}
+
+// setBlockEntity changes the block entity at pos, a nil Data removes it.
+func (w *World) setBlockEntity(pos proto.Position, entity BlockEntity) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	chunk, ok := w.chunks[ChunkPos{pos.X >> 4, pos.Z >> 4}]
+	if !ok {
+		return
+	}
+	if entity.Data == nil {
+		delete(chunk.BlockEntities, pos)
+		return
+	}
+	chunk.BlockEntities[pos] = entity
+}
+
+func (w *World) loadChunk(pos ChunkPos, data []byte, entities []proto.ChunkBlockEntity) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	buf := bytes.NewBuffer(data)
+	chunk := &Chunk{
+		Sections:      make([]proto.ChunkSection, w.sections()),
+		BlockEntities: make(map[proto.Position]BlockEntity, len(entities)),
+	}
+	for i := range chunk.Sections {
+		if _, err := chunk.Sections[i].ReadFrom(buf); err != nil {
+			return fmt.Errorf("cannot read section %d of chunk %v: %w", i, pos, err)
+		}
+	}
+
+	for _, e := range entities {
+		at := proto.Position{
+			X: pos.X<<4 | int32(e.PackedXZ>>4),
+			Y: int32(e.Y),
+			Z: pos.Z<<4 | int32(e.PackedXZ&15),
+		}
+		chunk.BlockEntities[at] = BlockEntity{Type: int32(e.Type), Data: e.Data.Compound()}
+	}
+
+	w.chunks[pos] = chunk
+	return nil
+}
+
+// reset drops all chunks and changes the world bounds.
+func (w *World) reset(minY, height int32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.chunks = make(map[ChunkPos]*Chunk)
+	w.minY, w.height = minY, height
+}
+
+func (w *World) unloadChunk(pos ChunkPos) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.chunks, pos)
+}
+
+// Block returns the description of the block at given position. Client.Blocks
+// must be set.
+func (c *Client) Block(pos proto.Position) (Block, error) {
+	if c.Blocks == nil {
+		return Block{}, ErrNoBlockRegistry
+	}
+	return c.block(pos), nil
+}
+
+// block returns the block at pos for callers that made sure Client.Blocks
+// is set.
+func (c *Client) block(pos proto.Position) Block {
+	return c.Blocks.Block(c.World.BlockState(pos))
+}
+
+func (c *Client) handleChunkData(pk proto.Packet) error {
+	var chunk proto.ChunkDataResponse
+	if err := pk.Scan(&chunk); err != nil {
+		return err
+	}
+
+	return c.World.loadChunk(ChunkPos{int32(chunk.ChunkX), int32(chunk.ChunkZ)}, chunk.Data, chunk.BlockEntities)
+}
+
+func (c *Client) handleBlockEntityData(pk proto.Packet) error {
+	var update proto.BlockEntityDataResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	c.World.setBlockEntity(update.Location, BlockEntity{Type: int32(update.Type), Data: update.Data.Compound()})
+	return nil
+}
+
+func (c *Client) handleUnloadChunk(pk proto.Packet) error {
+	var chunk proto.UnloadChunkResponse
+	if err := pk.Scan(&chunk); err != nil {
+		return err
+	}
+
+	c.World.unloadChunk(ChunkPos{int32(chunk.ChunkX), int32(chunk.ChunkZ)})
+	return nil
+}
+
+func (c *Client) handleBlockUpdate(pk proto.Packet) error {
+	var update proto.BlockUpdateResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	c.setBlockState(update.Location, int32(update.BlockID))
+	return nil
+}
+
+func (c *Client) handleUpdateSectionBlocks(pk proto.Packet) error {
+	var update proto.UpdateSectionBlocksResponse
+	if err := pk.Scan(&update); err != nil {
+		return err
+	}
+
+	for _, packed := range update.Blocks {
+		pos := proto.Position{
+			X: update.Section.X<<4 | int32(packed>>8&15),
+			Y: update.Section.Y<<4 | int32(packed&15),
+			Z: update.Section.Z<<4 | int32(packed>>4&15),
+		}
+		c.setBlockState(pos, int32(packed>>12))
+	}
+	return nil
+}
+
+func (c *Client) setBlockState(pos proto.Position, state int32) {
+	old, ok := c.World.setBlockState(pos, state)
+	if ok && old != state {
+		c.emit(BlockChangedEvent{Position: pos, Old: old, New: state})
+	}
+}